@@ -5,12 +5,18 @@ go 1.16
 require (
 	github.com/golang/mock v1.6.0
 	github.com/micro-business/go-core v0.6.2
+	github.com/mmcloughlin/geohash v0.10.0
+	github.com/oschwald/maxminddb-golang v1.3.1
+	github.com/pion/stun v0.3.5
 	github.com/prometheus/client_golang v1.11.0
 	github.com/robfig/cron/v3 v3.0.1
 	github.com/savsgio/atreugo/v11 v11.7.2
 	github.com/shengdoushi/base58 v1.0.0
 	github.com/spf13/cobra v1.1.3
 	go.uber.org/zap v1.17.0
+	golang.org/x/sys v0.0.0-20210603081109-ebe580a85c40
+	golang.org/x/text v0.3.6
+	k8s.io/api v0.21.2
 	k8s.io/apimachinery v0.21.2
 	k8s.io/client-go v0.21.2
 )
@@ -60,3 +60,15 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Address the HTTP server listens on. In the host network namespace it defaults to the loopback address, so the
+endpoints, e.g. POST /geolocation/refresh, are not reachable from outside the node
+*/}}
+{{- define "edge-core.httpHost" -}}
+{{- if .Values.pod.http.host }}
+{{- .Values.pod.http.host }}
+{{- else if .Values.pod.geolocation.networkChange.enabled }}
+{{- "127.0.0.1" }}
+{{- end }}
+{{- end }}
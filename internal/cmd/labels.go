@@ -0,0 +1,215 @@
+// Package cmd implements different commands that can be executed against EdgeCluster service
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"github.com/micro-business/go-core/pkg/util"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type decodedNode struct {
+	Node   string             `json:"node" yaml:"node"`
+	Labels *labels.NodeLabels `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Error is the reason the labels of the node could not be decoded, the other nodes are still reported
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newLabelsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "labels",
+		Short: "Work with the edgecloud9 node labels",
+	}
+
+	cmd.AddCommand(newLabelsDecodeCommand())
+
+	return cmd
+}
+
+func newLabelsDecodeCommand() *cobra.Command {
+	var kubeConfig string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "decode [node name]",
+		Short: "Decode the edgecloud9 labels of the given node, or of all nodes if no node name is given",
+		Args:  cobra.MaximumNArgs(1),
+		// Runtime errors such as an unreachable API server should not print the usage
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeName := ""
+			if len(args) == 1 {
+				nodeName = args[0]
+			}
+
+			decodedNodes, err := decodeNodeLabels(cmd.Context(), kubeConfig, nodeName)
+			if err != nil {
+				return err
+			}
+
+			return printDecodedNodes(decodedNodes, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&kubeConfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to KUBECONFIG, ~/.kube/config or the in-cluster config")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Output format, one of table, json or yaml")
+
+	return cmd
+}
+
+func decodeNodeLabels(ctx context.Context, kubeConfig string, nodeName string) ([]decodedNode, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	restConfig, err := getRestConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, commonErrors.NewUnknownErrorWithError("Failed to create client set", err)
+	}
+
+	nodes := []v1.Node{}
+
+	if nodeName != "" {
+		node, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, *node)
+	} else {
+		nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = nodeList.Items
+	}
+
+	decodedNodes := []decodedNode{}
+
+	for _, node := range nodes {
+		nodeLabels, err := labels.Decode(node.Labels)
+		if err != nil {
+			if nodeName != "" {
+				return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("Failed to decode the labels of node %s", node.Name), err)
+			}
+
+			decodedNodes = append(decodedNodes, decodedNode{Node: node.Name, Error: err.Error()})
+
+			continue
+		}
+
+		decodedNodes = append(decodedNodes, decodedNode{Node: node.Name, Labels: nodeLabels})
+	}
+
+	return decodedNodes, nil
+}
+
+func getRestConfig(kubeConfig string) (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfig
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err == nil {
+		return restConfig, nil
+	}
+
+	if kubeConfig != "" {
+		return nil, err
+	}
+
+	return rest.InClusterConfig()
+}
+
+func printDecodedNodes(decodedNodes []decodedNode, output string) error {
+	switch output {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(decodedNodes)
+	case "yaml":
+		util.PrintYAML(decodedNodes)
+
+		return nil
+	case "table":
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NODE\tPUBLIC IP\tHOSTNAME\tLOCATION\tLOC\tPROVIDER\tREACHABILITY\tLAST UPDATED")
+
+		for _, decodedNode := range decodedNodes {
+			if decodedNode.Error != "" {
+				fmt.Fprintf(writer, "%s\t<error: %s>\n", decodedNode.Node, decodedNode.Error)
+
+				continue
+			}
+
+			geolocation := decodedNode.Labels.Geolocation
+			if geolocation == nil {
+				geolocation = &labels.Geolocation{}
+			}
+
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				decodedNode.Node,
+				valueOrNone(geolocation.Ip),
+				valueOrNone(geolocation.Hostname),
+				valueOrNone(joinNonEmpty(geolocation.City, geolocation.Region, geolocation.Country)),
+				valueOrNone(geolocation.Loc),
+				valueOrNone(geolocation.Provider),
+				valueOrNone(decodedNode.Labels.Reachability),
+				valueOrNone(formatTime(decodedNode.Labels.GeolocationLastUpdatedTime)))
+		}
+
+		return writer.Flush()
+	default:
+		return commonErrors.NewArgumentError("output", fmt.Sprintf("output format %s is not supported", output))
+	}
+}
+
+func joinNonEmpty(values ...string) string {
+	nonEmptyValues := []string{}
+
+	for _, value := range values {
+		if value != "" {
+			nonEmptyValues = append(nonEmptyValues, value)
+		}
+	}
+
+	return strings.Join(nonEmptyValues, ", ")
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.Format(time.RFC3339)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
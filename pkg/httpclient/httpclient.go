@@ -0,0 +1,47 @@
+// Package httpclient implements the HTTP clients the edge-core calls the public IP resolvers and the geolocation
+// providers with
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+)
+
+var clients = struct {
+	sync.Mutex
+	items map[configuration.AddressFamily]*http.Client
+}{items: map[configuration.AddressFamily]*http.Client{}}
+
+// ForAddressFamily returns the HTTP client that only connects using the given address family, so the remote
+// service sees the node public IP address of that family. The client is shared, so its connections are reused
+// across calls.
+// addressFamily: Mandatory. The address family to connect with
+// Returns the HTTP client of the address family
+func ForAddressFamily(addressFamily configuration.AddressFamily) *http.Client {
+	clients.Lock()
+	defer clients.Unlock()
+
+	if client, ok := clients.items[addressFamily]; ok {
+		return client
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, addressFamily.Network("tcp"), address)
+	}
+
+	client := &http.Client{Transport: transport}
+	clients.items[addressFamily] = client
+
+	return client
+}
@@ -0,0 +1 @@
+package labels_test
@@ -0,0 +1,57 @@
+package labels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"github.com/mmcloughlin/geohash"
+)
+
+const (
+	// GeohashLabelPrefix is the prefix of the labels that contain the geohash of the node location, followed by
+	// the precision, e.g. edgecloud9.geolocation.geohash6. Unlike the other labels the values are not encoded, as
+	// geohashes are valid label values, so they can be used in node affinity rules.
+	GeohashLabelPrefix = GeolocationLabelPrefix + "geohash"
+
+	// MaxGeohashPrecision is the maximum supported geohash precision
+	MaxGeohashPrecision = 12
+)
+
+// Geohashes computes the geohashes of the given location at the given precisions
+// loc: Mandatory. The comma separated latitude and longitude
+// precisions: Mandatory. The geohash precisions, each between 1 and MaxGeohashPrecision
+// Returns the geohashes keyed by their precision or error if the location can not be parsed
+func Geohashes(loc string, precisions []int) (map[int]string, error) {
+	geohashes := map[int]string{}
+
+	if len(precisions) == 0 {
+		return geohashes, nil
+	}
+
+	coordinates := strings.Split(loc, ",")
+	if len(coordinates) != 2 {
+		return nil, commonErrors.NewArgumentError("loc", fmt.Sprintf("%s is not a comma separated latitude and longitude", loc))
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+	if err != nil {
+		return nil, commonErrors.NewArgumentError("loc", fmt.Sprintf("latitude of %s is not a number", loc))
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+	if err != nil {
+		return nil, commonErrors.NewArgumentError("loc", fmt.Sprintf("longitude of %s is not a number", loc))
+	}
+
+	for _, precision := range precisions {
+		if precision < 1 || precision > MaxGeohashPrecision {
+			return nil, commonErrors.NewArgumentError("precisions", fmt.Sprintf("geohash precision %d is not supported", precision))
+		}
+
+		geohashes[precision] = geohash.EncodeWithPrecision(latitude, longitude, uint(precision))
+	}
+
+	return geohashes, nil
+}
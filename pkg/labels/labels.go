@@ -0,0 +1,348 @@
+// Package labels implements encoding and decoding of the edgecloud9 node labels the edge-core writes the public IP
+// address and geolocation details of the node into
+package labels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"github.com/shengdoushi/base58"
+)
+
+const (
+	// PublicLabelPrefix is the prefix of the labels that contain the node public IP address details
+	PublicLabelPrefix = "edgecloud9.public."
+	// GeolocationLabelPrefix is the prefix of the labels that contain the node geolocation details
+	GeolocationLabelPrefix = "edgecloud9.geolocation."
+
+	// PublicLastUpdatedTimeLabel is the label that contains the last time the public IP address details were updated
+	PublicLastUpdatedTimeLabel = PublicLabelPrefix + "lastUpdatedTime"
+	// GeolocationLastUpdatedTimeLabel is the label that contains the last time the geolocation details were updated
+	GeolocationLastUpdatedTimeLabel = GeolocationLabelPrefix + "lastUpdatedTime"
+	// LastCheckedTimeLabel is the label that contains the last time the details were checked, whether or not
+	// they changed
+	LastCheckedTimeLabel = GeolocationLabelPrefix + "lastCheckedTime"
+	// GeolocationManualLabel is the label operators set to control whether the geolocation details are updated
+	GeolocationManualLabel = GeolocationLabelPrefix + "manual"
+
+	// IPv4Infix is inserted after the label prefixes for the labels that contain the IPv4 details
+	IPv4Infix = "ipv4."
+	// IPv6Infix is inserted after the label prefixes for the labels that contain the IPv6 details
+	IPv6Infix = "ipv6."
+)
+
+// Alphabet is the base58 alphabet the label values are encoded with. It only contains characters that are
+// valid in label values.
+var Alphabet = base58.NewAlphabet("ABCDEFGHJKLMNPQRSTUVWXYZ123456789abcdefghijkmnopqrstuvwxyz")
+
+// Geolocation contains the public IP address and geolocation details of a node
+type Geolocation struct {
+	Ip       string `json:"ip" yaml:"ip"`
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+	NatType  string `json:"natType,omitempty" yaml:"natType,omitempty"`
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Loc      string `json:"loc,omitempty" yaml:"loc,omitempty"`
+	City     string `json:"city,omitempty" yaml:"city,omitempty"`
+	Region   string `json:"region,omitempty" yaml:"region,omitempty"`
+	Country  string `json:"country,omitempty" yaml:"country,omitempty"`
+	Org      string `json:"org,omitempty" yaml:"org,omitempty"`
+	Postal   string `json:"postal,omitempty" yaml:"postal,omitempty"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+
+	// Source is where the location comes from, ManualSource or ProviderSource. It is not encoded, so overridden
+	// nodes can be selected.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Asn, OrgName and NetworkType are derived from Org. Asn and NetworkType are not encoded, so they can be used
+	// in node affinity rules.
+	Asn         string `json:"asn,omitempty" yaml:"asn,omitempty"`
+	OrgName     string `json:"orgName,omitempty" yaml:"orgName,omitempty"`
+	NetworkType string `json:"networkType,omitempty" yaml:"networkType,omitempty"`
+}
+
+// NodeLabels contains the decoded edgecloud9 node labels
+type NodeLabels struct {
+	// PublicLastUpdatedTime is the last time the public IP address details were updated
+	PublicLastUpdatedTime *time.Time `json:"publicLastUpdatedTime,omitempty" yaml:"publicLastUpdatedTime,omitempty"`
+
+	// GeolocationLastUpdatedTime is the last time the geolocation details were updated
+	GeolocationLastUpdatedTime *time.Time `json:"geolocationLastUpdatedTime,omitempty" yaml:"geolocationLastUpdatedTime,omitempty"`
+
+	// LastCheckedTime is the last time the details were checked, whether or not they changed
+	LastCheckedTime *time.Time `json:"lastCheckedTime,omitempty" yaml:"lastCheckedTime,omitempty"`
+
+	// Geolocation is the primary public IP address and geolocation details of the node
+	Geolocation *Geolocation `json:"geolocation,omitempty" yaml:"geolocation,omitempty"`
+
+	// IPv4 is the IPv4 public IP address and geolocation details of the node
+	IPv4 *Geolocation `json:"ipv4,omitempty" yaml:"ipv4,omitempty"`
+
+	// IPv6 is the IPv6 public IP address and geolocation details of the node
+	IPv6 *Geolocation `json:"ipv6,omitempty" yaml:"ipv6,omitempty"`
+
+	// Geohashes are the geohashes of the primary location keyed by their precision
+	Geohashes map[int]string `json:"geohashes,omitempty" yaml:"geohashes,omitempty"`
+
+	// Reachability is whether the node can be reached from the internet through its public IP address, one of
+	// the Reachabilities
+	Reachability string `json:"reachability,omitempty" yaml:"reachability,omitempty"`
+}
+
+// EncodeValue encodes the given value so it can be used as a label value
+// value: Mandatory. The value to encode
+// Returns the encoded value
+func EncodeValue(value string) string {
+	return base58.Encode([]byte(value), Alphabet)
+}
+
+// DecodeValue decodes the given label value
+// value: Mandatory. The label value to decode
+// Returns the decoded value or error if the label value is not encoded using the Alphabet
+func DecodeValue(value string) (string, error) {
+	decoded, err := base58.Decode(value, Alphabet)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
+
+// Encode encodes the given node labels into label keys and values. Only the fields that are set are encoded,
+// so the result can be used as a merge patch that leaves the other labels untouched.
+// nodeLabels: Mandatory. The node labels to encode
+// Returns the encoded labels
+func Encode(nodeLabels NodeLabels) map[string]string {
+	labels := map[string]string{}
+
+	if nodeLabels.PublicLastUpdatedTime != nil {
+		labels[PublicLastUpdatedTimeLabel] = EncodeValue(nodeLabels.PublicLastUpdatedTime.Format(time.RFC3339Nano))
+	}
+
+	if nodeLabels.GeolocationLastUpdatedTime != nil {
+		labels[GeolocationLastUpdatedTimeLabel] = EncodeValue(nodeLabels.GeolocationLastUpdatedTime.Format(time.RFC3339Nano))
+	}
+
+	if nodeLabels.LastCheckedTime != nil {
+		labels[LastCheckedTimeLabel] = EncodeValue(nodeLabels.LastCheckedTime.Format(time.RFC3339Nano))
+	}
+
+	encodeGeolocation(labels, "", nodeLabels.Geolocation)
+	encodeGeolocation(labels, IPv4Infix, nodeLabels.IPv4)
+	encodeGeolocation(labels, IPv6Infix, nodeLabels.IPv6)
+
+	for precision, geohash := range nodeLabels.Geohashes {
+		labels[GeohashLabelPrefix+strconv.Itoa(precision)] = geohash
+	}
+
+	if nodeLabels.Reachability != "" {
+		labels[ReachabilityLabel] = nodeLabels.Reachability
+	}
+
+	return labels
+}
+
+// Decode decodes the edgecloud9 labels from the given node labels. Labels that are not set are left empty.
+// labels: Mandatory. The node labels
+// Returns the decoded node labels or error if any of the edgecloud9 labels can not be decoded
+func Decode(labels map[string]string) (*NodeLabels, error) {
+	nodeLabels := &NodeLabels{}
+
+	var err error
+
+	if nodeLabels.PublicLastUpdatedTime, err = decodeTime(labels, PublicLastUpdatedTimeLabel); err != nil {
+		return nil, err
+	}
+
+	if nodeLabels.GeolocationLastUpdatedTime, err = decodeTime(labels, GeolocationLastUpdatedTimeLabel); err != nil {
+		return nil, err
+	}
+
+	if nodeLabels.LastCheckedTime, err = decodeTime(labels, LastCheckedTimeLabel); err != nil {
+		return nil, err
+	}
+
+	if nodeLabels.Geolocation, err = decodeGeolocation(labels, ""); err != nil {
+		return nil, err
+	}
+
+	if nodeLabels.IPv4, err = decodeGeolocation(labels, IPv4Infix); err != nil {
+		return nil, err
+	}
+
+	if nodeLabels.IPv6, err = decodeGeolocation(labels, IPv6Infix); err != nil {
+		return nil, err
+	}
+
+	nodeLabels.Geohashes = decodeGeohashes(labels)
+	nodeLabels.Reachability = labels[ReachabilityLabel]
+
+	return nodeLabels, nil
+}
+
+// Diff returns the labels from the desired labels that are missing from or have a different value in the current
+// labels, so only the changed keys need to be patched
+// current: Mandatory. The current node labels
+// desired: Mandatory. The desired labels, usually the result of Encode
+// Returns the changed labels
+func Diff(current map[string]string, desired map[string]string) map[string]string {
+	changed := map[string]string{}
+
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			changed[key] = value
+		}
+	}
+
+	return changed
+}
+
+func encodeGeolocation(labels map[string]string, infix string, geolocation *Geolocation) {
+	if geolocation == nil {
+		return
+	}
+
+	publicPrefix := PublicLabelPrefix + infix
+	geolocationPrefix := GeolocationLabelPrefix + infix
+
+	port := ""
+	if geolocation.Port != 0 {
+		port = strconv.Itoa(geolocation.Port)
+	}
+
+	// The public IP address labels are left out when the public IP address is not known, e.g. when the location
+	// only comes from the override annotations
+	if geolocation.Ip != "" {
+		labels[publicPrefix+"ip"] = EncodeValue(geolocation.Ip)
+		labels[publicPrefix+"port"] = EncodeValue(port)
+		labels[publicPrefix+"natType"] = EncodeValue(geolocation.NatType)
+		labels[publicPrefix+"hostname"] = EncodeValue(geolocation.Hostname)
+	}
+
+	labels[geolocationPrefix+"loc"] = EncodeValue(geolocation.Loc)
+	labels[geolocationPrefix+"city"] = EncodeValue(geolocation.City)
+	labels[geolocationPrefix+"region"] = EncodeValue(geolocation.Region)
+	labels[geolocationPrefix+"country"] = EncodeValue(geolocation.Country)
+	labels[geolocationPrefix+"org"] = EncodeValue(geolocation.Org)
+	labels[geolocationPrefix+"postal"] = EncodeValue(geolocation.Postal)
+	labels[geolocationPrefix+"timezone"] = EncodeValue(geolocation.Timezone)
+	labels[geolocationPrefix+"provider"] = EncodeValue(geolocation.Provider)
+	labels[geolocationPrefix+"source"] = geolocation.Source
+	labels[geolocationPrefix+"asn"] = geolocation.Asn
+	labels[geolocationPrefix+"orgName"] = EncodeValue(geolocation.OrgName)
+	labels[geolocationPrefix+"networkType"] = geolocation.NetworkType
+}
+
+func decodeGeolocation(labels map[string]string, infix string) (*Geolocation, error) {
+	publicPrefix := PublicLabelPrefix + infix
+	geolocationPrefix := GeolocationLabelPrefix + infix
+
+	// The public IP address, or the provider if the public IP address is not known, is always written, so the
+	// group is considered not set if both are missing
+	_, hasIp := labels[publicPrefix+"ip"]
+	_, hasProvider := labels[geolocationPrefix+"provider"]
+
+	if !hasIp && !hasProvider {
+		return nil, nil
+	}
+
+	geolocation := &Geolocation{}
+	port := ""
+
+	fields := map[string]*string{
+		publicPrefix + "ip":            &geolocation.Ip,
+		publicPrefix + "port":          &port,
+		publicPrefix + "natType":       &geolocation.NatType,
+		publicPrefix + "hostname":      &geolocation.Hostname,
+		geolocationPrefix + "loc":      &geolocation.Loc,
+		geolocationPrefix + "city":     &geolocation.City,
+		geolocationPrefix + "region":   &geolocation.Region,
+		geolocationPrefix + "country":  &geolocation.Country,
+		geolocationPrefix + "org":      &geolocation.Org,
+		geolocationPrefix + "postal":   &geolocation.Postal,
+		geolocationPrefix + "timezone": &geolocation.Timezone,
+		geolocationPrefix + "provider": &geolocation.Provider,
+		geolocationPrefix + "orgName":  &geolocation.OrgName,
+	}
+
+	plainFields := map[string]*string{
+		geolocationPrefix + "asn":         &geolocation.Asn,
+		geolocationPrefix + "networkType": &geolocation.NetworkType,
+		geolocationPrefix + "source":      &geolocation.Source,
+	}
+
+	for key, field := range plainFields {
+		*field = labels[key]
+	}
+
+	for key, field := range fields {
+		value, ok := labels[key]
+		if !ok {
+			continue
+		}
+
+		decoded, err := DecodeValue(value)
+		if err != nil {
+			return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("failed to decode label %s", key), err)
+		}
+
+		*field = decoded
+	}
+
+	if port != "" {
+		value, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("failed to convert label %s to integer", publicPrefix+"port"), err)
+		}
+
+		geolocation.Port = value
+	}
+
+	return geolocation, nil
+}
+
+// decodeGeohashes returns the geohashes of the geohash labels keyed by their precision, or nil if there are none
+func decodeGeohashes(labels map[string]string) map[int]string {
+	var geohashes map[int]string
+
+	for key, value := range labels {
+		if !strings.HasPrefix(key, GeohashLabelPrefix) {
+			continue
+		}
+
+		precision, err := strconv.Atoi(strings.TrimPrefix(key, GeohashLabelPrefix))
+		if err != nil || precision < 1 || precision > MaxGeohashPrecision {
+			continue
+		}
+
+		if geohashes == nil {
+			geohashes = map[int]string{}
+		}
+
+		geohashes[precision] = value
+	}
+
+	return geohashes
+}
+
+func decodeTime(labels map[string]string, key string) (*time.Time, error) {
+	value, ok := labels[key]
+	if !ok {
+		return nil, nil
+	}
+
+	decoded, err := DecodeValue(value)
+	if err != nil {
+		return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("failed to decode label %s", key), err)
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, decoded)
+	if err != nil {
+		return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("failed to parse label %s as time", key), err)
+	}
+
+	return &parsed, nil
+}
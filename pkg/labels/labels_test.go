@@ -0,0 +1,163 @@
+package labels_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	updatedTime := time.Date(2021, 6, 1, 10, 30, 0, 123456789, time.UTC)
+	checkedTime := updatedTime.Add(time.Hour)
+	asn, orgName := labels.ParseOrg("AS13335 Cloudflare, Inc.")
+
+	nodeLabels := labels.NodeLabels{
+		PublicLastUpdatedTime:      &updatedTime,
+		GeolocationLastUpdatedTime: &updatedTime,
+		LastCheckedTime:            &checkedTime,
+		Geolocation: &labels.Geolocation{
+			Ip:          "2606:4700:4700::1111",
+			Hostname:    "one.one.one.one",
+			Loc:         "-37.8140,144.9633",
+			City:        "Melbourne",
+			Region:      "Victoria",
+			Country:     "AU",
+			Org:         "AS13335 Cloudflare, Inc.",
+			Postal:      "3000",
+			Timezone:    "Australia/Melbourne",
+			Provider:    "ipinfo",
+			Source:      labels.ProviderSource,
+			Asn:         asn,
+			OrgName:     orgName,
+			NetworkType: labels.NetworkTypeHosting,
+		},
+		IPv4: &labels.Geolocation{
+			Ip:       "1.1.1.1",
+			Port:     3478,
+			NatType:  "cone",
+			Provider: "maxmind",
+		},
+		Geohashes:    map[int]string{4: "r1r0", 6: "r1r0fs"},
+		Reachability: labels.ReachabilityCGNAT,
+	}
+
+	encoded := labels.Encode(nodeLabels)
+
+	for key, value := range encoded {
+		if len(value) > 63 {
+			t.Errorf("label %s value %q is longer than 63 characters", key, value)
+		}
+	}
+
+	if encoded[labels.GeolocationLabelPrefix+"asn"] != "13335" {
+		t.Errorf("expected the asn label to be stored unencoded, got %q", encoded[labels.GeolocationLabelPrefix+"asn"])
+	}
+
+	if encoded[labels.GeohashLabelPrefix+"6"] != "r1r0fs" {
+		t.Errorf("expected the geohash label to be stored unencoded, got %q", encoded[labels.GeohashLabelPrefix+"6"])
+	}
+
+	if encoded[labels.ReachabilityLabel] != labels.ReachabilityCGNAT {
+		t.Errorf("expected the reachability label to be stored unencoded, got %q", encoded[labels.ReachabilityLabel])
+	}
+
+	decoded, err := labels.Decode(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode the labels: %v", err)
+	}
+
+	if !reflect.DeepEqual(*decoded, nodeLabels) {
+		t.Errorf("expected %+v, got %+v", nodeLabels, *decoded)
+	}
+
+	if decoded.IPv6 != nil {
+		t.Errorf("expected the missing IPv6 group to decode to nil, got %+v", decoded.IPv6)
+	}
+}
+
+func TestDecodeInvalidLabel(t *testing.T) {
+	_, err := labels.Decode(map[string]string{labels.PublicLabelPrefix + "ip": "0OIl"})
+	if err == nil {
+		t.Error("expected an error decoding a label value that is not base58")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current := map[string]string{
+		"unchanged": "a",
+		"changed":   "b",
+		"unrelated": "c",
+	}
+
+	desired := map[string]string{
+		"unchanged": "a",
+		"changed":   "B",
+		"added":     "d",
+	}
+
+	expected := map[string]string{
+		"changed": "B",
+		"added":   "d",
+	}
+
+	if changed := labels.Diff(current, desired); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected %v, got %v", expected, changed)
+	}
+}
+
+func TestParseOrg(t *testing.T) {
+	tests := []struct {
+		org     string
+		asn     string
+		orgName string
+	}{
+		{org: "AS13335 Cloudflare, Inc.", asn: "13335", orgName: "Cloudflare, Inc."},
+		{org: "as7922  Comcast Cable ", asn: "7922", orgName: "Comcast Cable"},
+		{org: "AS15169", asn: "15169", orgName: ""},
+		{org: "Cloudflare, Inc.", asn: "", orgName: "Cloudflare, Inc."},
+		{org: "ASN Example", asn: "", orgName: "ASN Example"},
+		{org: "", asn: "", orgName: ""},
+	}
+
+	for _, test := range tests {
+		asn, orgName := labels.ParseOrg(test.org)
+		if asn != test.asn || orgName != test.orgName {
+			t.Errorf("ParseOrg(%q): expected (%q, %q), got (%q, %q)", test.org, test.asn, test.orgName, asn, orgName)
+		}
+	}
+}
+
+func TestEncodeWithoutIp(t *testing.T) {
+	nodeLabels := labels.NodeLabels{
+		Geolocation: &labels.Geolocation{
+			Loc:      "52.5200,13.4050",
+			City:     "Berlin",
+			Provider: labels.ManualProvider,
+			Source:   labels.ManualSource,
+		},
+	}
+
+	encoded := labels.Encode(nodeLabels)
+
+	if encoded[labels.GeolocationLabelPrefix+"source"] != labels.ManualSource {
+		t.Errorf("expected the source label to be stored unencoded, got %q", encoded[labels.GeolocationLabelPrefix+"source"])
+	}
+
+	for key := range encoded {
+		if strings.HasPrefix(key, labels.PublicLabelPrefix) {
+			t.Errorf("expected no public IP address labels without a public IP address, got %s", key)
+		}
+	}
+
+	decoded, err := labels.Decode(encoded)
+	if err != nil {
+		t.Fatalf("failed to decode the labels: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Geolocation, nodeLabels.Geolocation) {
+		t.Errorf("expected %+v, got %+v", nodeLabels.Geolocation, decoded.Geolocation)
+	}
+}
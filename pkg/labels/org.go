@@ -0,0 +1,42 @@
+package labels
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// NetworkTypeResidential determines that the autonomous system belongs to a residential or fixed line ISP
+	NetworkTypeResidential = "residential"
+	// NetworkTypeMobile determines that the autonomous system belongs to a mobile carrier
+	NetworkTypeMobile = "mobile"
+	// NetworkTypeHosting determines that the autonomous system belongs to a hosting or datacenter provider
+	NetworkTypeHosting = "hosting"
+	// NetworkTypeUnknown determines that the autonomous system is not in the configured ASN map
+	NetworkTypeUnknown = "unknown"
+)
+
+// ParseOrg splits the organization returned by the providers, e.g. "AS13335 Cloudflare, Inc.", into the
+// autonomous system number and the organization name
+// org: Mandatory. The organization returned by the providers
+// Returns the numeric autonomous system number, or empty if the organization does not start with one, and the
+// organization name
+func ParseOrg(org string) (string, string) {
+	org = strings.TrimSpace(org)
+	fields := strings.SplitN(org, " ", 2)
+
+	if len(fields[0]) < 3 || !strings.EqualFold(fields[0][:2], "AS") {
+		return "", org
+	}
+
+	asn, err := strconv.ParseUint(fields[0][2:], 10, 32)
+	if err != nil {
+		return "", org
+	}
+
+	if len(fields) == 1 {
+		return strconv.FormatUint(asn, 10), ""
+	}
+
+	return strconv.FormatUint(asn, 10), strings.TrimSpace(fields[1])
+}
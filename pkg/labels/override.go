@@ -0,0 +1,108 @@
+package labels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	commonErrors "github.com/micro-business/go-core/system/errors"
+)
+
+const (
+	// OverrideAnnotationPrefix is the prefix of the node annotations operators set to supply the location of the
+	// node by hand, e.g. edgecloud9.geolocation.override.latitude
+	OverrideAnnotationPrefix = "edgecloud9.geolocation.override."
+
+	// ManualProvider is the provider recorded in the labels when the override annotations are the only source of
+	// the details, as no public IP address is known
+	ManualProvider = "manual"
+
+	// ManualSource is the source recorded in the labels when the location comes from the override annotations
+	ManualSource = "manual"
+	// ProviderSource is the source recorded in the labels when the location is resolved by the providers
+	ProviderSource = "provider"
+)
+
+// Override contains the human readable location of the node supplied through the override annotations. Fields
+// that are not set are left as resolved by the geolocation providers.
+type Override struct {
+	Latitude  string `json:"latitude,omitempty" yaml:"latitude,omitempty"`
+	Longitude string `json:"longitude,omitempty" yaml:"longitude,omitempty"`
+	City      string `json:"city,omitempty" yaml:"city,omitempty"`
+	Region    string `json:"region,omitempty" yaml:"region,omitempty"`
+	Country   string `json:"country,omitempty" yaml:"country,omitempty"`
+	Postal    string `json:"postal,omitempty" yaml:"postal,omitempty"`
+	Timezone  string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// ParseOverride parses the override annotations of a node
+// annotations: Mandatory. The node annotations
+// Returns the override, or nil if none of the override annotations is set, or error if the annotations are invalid
+func ParseOverride(annotations map[string]string) (*Override, error) {
+	override := &Override{}
+	fields := map[string]*string{
+		OverrideAnnotationPrefix + "latitude":  &override.Latitude,
+		OverrideAnnotationPrefix + "longitude": &override.Longitude,
+		OverrideAnnotationPrefix + "city":      &override.City,
+		OverrideAnnotationPrefix + "region":    &override.Region,
+		OverrideAnnotationPrefix + "country":   &override.Country,
+		OverrideAnnotationPrefix + "postal":    &override.Postal,
+		OverrideAnnotationPrefix + "timezone":  &override.Timezone,
+	}
+
+	found := false
+
+	for key, field := range fields {
+		if value := strings.TrimSpace(annotations[key]); value != "" {
+			*field = value
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	if (override.Latitude == "") != (override.Longitude == "") {
+		return nil, commonErrors.NewArgumentError(
+			"annotations",
+			fmt.Sprintf("%slatitude and %slongitude must be set together", OverrideAnnotationPrefix, OverrideAnnotationPrefix))
+	}
+
+	if override.Latitude != "" {
+		if err := validateCoordinate(override.Latitude, "latitude", 90); err != nil {
+			return nil, err
+		}
+
+		if err := validateCoordinate(override.Longitude, "longitude", 180); err != nil {
+			return nil, err
+		}
+	}
+
+	return override, nil
+}
+
+// Loc returns the comma separated latitude and longitude of the override in the same format as the loc label
+// Returns the latitude and longitude, or empty string if the override does not set them
+func (override *Override) Loc() string {
+	if override.Latitude == "" {
+		return ""
+	}
+
+	return override.Latitude + "," + override.Longitude
+}
+
+func validateCoordinate(value string, name string, limit float64) error {
+	coordinate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return commonErrors.NewArgumentError("annotations", fmt.Sprintf("%s%s (%s) is not a number", OverrideAnnotationPrefix, name, value))
+	}
+
+	if coordinate < -limit || coordinate > limit {
+		return commonErrors.NewArgumentError(
+			"annotations",
+			fmt.Sprintf("%s%s (%s) must be between %v and %v", OverrideAnnotationPrefix, name, value, -limit, limit))
+	}
+
+	return nil
+}
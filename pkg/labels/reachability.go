@@ -0,0 +1,19 @@
+package labels
+
+const (
+	// ReachabilityLabel is the label that contains whether the node can be reached from the internet through its
+	// public IP address. Unlike the other labels the value is not encoded, so it can be used in node affinity rules.
+	ReachabilityLabel = PublicLabelPrefix + "reachability"
+
+	// ReachabilityDirect determines that the public IP address is assigned to the node itself
+	ReachabilityDirect = "direct"
+	// ReachabilityNAT determines that the node is behind a NAT the operator can usually forward ports on
+	ReachabilityNAT = "nat"
+	// ReachabilityCGNAT determines that the node is behind a carrier grade NAT, so inbound connections can not reach it
+	ReachabilityCGNAT = "cgnat"
+	// ReachabilityUnknown determines that the node addresses do not tell how the node reaches the internet
+	ReachabilityUnknown = "unknown"
+)
+
+// Reachabilities lists all the values of the reachability label
+var Reachabilities = []string{ReachabilityDirect, ReachabilityNAT, ReachabilityCGNAT, ReachabilityUnknown}
@@ -0,0 +1 @@
+package nodegeolocation_test
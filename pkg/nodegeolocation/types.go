@@ -0,0 +1,98 @@
+// Package nodegeolocation defines the NodeGeolocation custom resource the edge-core maintains for every node it
+// runs on. The resource carries the same details as the edgecloud9 node labels in plain text, together with a
+// bounded history of the previous locations of the node.
+package nodegeolocation
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Group is the API group of the NodeGeolocation custom resource
+	Group = "edgecloud9.io"
+
+	// Version is the API version of the NodeGeolocation custom resource
+	Version = "v1alpha1"
+
+	// Kind is the kind of the NodeGeolocation custom resource
+	Kind = "NodeGeolocation"
+
+	// Resource is the plural resource name of the NodeGeolocation custom resource
+	Resource = "nodegeolocations"
+)
+
+// GroupVersionResource identifies the NodeGeolocation custom resource when using the dynamic client
+var GroupVersionResource = schema.GroupVersionResource{Group: Group, Version: Version, Resource: Resource}
+
+// NodeGeolocation is the cluster-scoped custom resource that contains the public IP address and geolocation
+// details of a node. It has the same name as the node and is owned by it, so it is removed with the node.
+type NodeGeolocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeGeolocationSpec   `json:"spec,omitempty"`
+	Status NodeGeolocationStatus `json:"status,omitempty"`
+}
+
+// NodeGeolocationSpec identifies the node the NodeGeolocation belongs to
+type NodeGeolocationSpec struct {
+	// NodeName is the name of the node
+	NodeName string `json:"nodeName"`
+}
+
+// Location contains the public IP address and geolocation details of a node at a point in time
+type Location struct {
+	Ip        string `json:"ip,omitempty"`
+	Hostname  string `json:"hostname,omitempty"`
+	Latitude  string `json:"latitude,omitempty"`
+	Longitude string `json:"longitude,omitempty"`
+	City      string `json:"city,omitempty"`
+	Region    string `json:"region,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Provider  string `json:"provider,omitempty"`
+	Source    string `json:"source,omitempty"`
+}
+
+// SamePlace determines whether the given location points to the same public IP address and place. The provider
+// is not compared, as providers in a chain can take turns answering without the node moving.
+// other: Mandatory. The location to compare with
+// Returns true if both locations point to the same public IP address and place otherwise returns false
+func (location Location) SamePlace(other Location) bool {
+	location.Provider = ""
+	other.Provider = ""
+
+	return location == other
+}
+
+// HistoryEntry is a previous location of a node
+type HistoryEntry struct {
+	Location `json:",inline"`
+
+	// FirstSeenTime is the time the location was first observed
+	FirstSeenTime metav1.Time `json:"firstSeenTime"`
+
+	// LastSeenTime is the last time the location was observed before it changed
+	LastSeenTime metav1.Time `json:"lastSeenTime"`
+}
+
+// NodeGeolocationStatus contains the current location of the node and the history of its previous locations
+type NodeGeolocationStatus struct {
+	Location `json:",inline"`
+
+	// IPv4 is the IPv4 public IP address of the node, if it has one
+	IPv4 string `json:"ipv4,omitempty"`
+
+	// IPv6 is the IPv6 public IP address of the node, if it has one
+	IPv6 string `json:"ipv6,omitempty"`
+
+	// FirstSeenTime is the time the current location was first observed
+	FirstSeenTime *metav1.Time `json:"firstSeenTime,omitempty"`
+
+	// LastSuccessTime is the last time the location was successfully resolved. While the location does not change
+	// it is only moved on the heartbeat interval.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+
+	// History contains the previous locations of the node, the most recent first
+	History []HistoryEntry `json:"history,omitempty"`
+}
@@ -2,17 +2,31 @@
 package util
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 
 	"github.com/decentralized-cloud/edge-core/services/configuration"
 	"github.com/decentralized-cloud/edge-core/services/cron/ipgeolocation"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/geolocation/chain"
+	"github.com/decentralized-cloud/edge-core/services/geolocation/ipinfo"
+	"github.com/decentralized-cloud/edge-core/services/geolocation/maxmind"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	"github.com/decentralized-cloud/edge-core/services/publicip/dns"
+	publicIPHttp "github.com/decentralized-cloud/edge-core/services/publicip/http"
+	"github.com/decentralized-cloud/edge-core/services/publicip/stun"
+	"github.com/decentralized-cloud/edge-core/services/state"
+	stateFile "github.com/decentralized-cloud/edge-core/services/state/file"
 	"github.com/decentralized-cloud/edge-core/services/transport/http"
+	commonErrors "github.com/micro-business/go-core/system/errors"
 	"go.uber.org/zap"
 )
 
 var configurationService configuration.ConfigurationContract
+var geolocationProvider chain.ChainProviderContract
+var stateStore state.StateStoreContract
 
 // StartService setups all dependecies required to start the EdgeCluster service and
 // start the service
@@ -32,14 +46,19 @@ func StartService() {
 
 	geolocationUpdaterService, err := ipgeolocation.NewCronService(
 		logger,
-		configurationService)
+		configurationService,
+		geolocationProvider,
+		stateStore)
 	if err != nil {
 		logger.Fatal("Failed to create Geolocation Updater service", zap.Error(err))
 	}
 
 	httpTansportService, err := http.NewTransportService(
 		logger,
-		configurationService)
+		configurationService,
+		stateStore,
+		geolocationUpdaterService,
+		geolocationProvider)
 	if err != nil {
 		logger.Fatal("Failed to create HTTP transport service", zap.Error(err))
 	}
@@ -86,5 +105,109 @@ func setupDependencies(logger *zap.Logger) (err error) {
 		return
 	}
 
+	if stateStore, err = stateFile.NewFileStore(logger, configurationService); err != nil {
+		return
+	}
+
+	if geolocationProvider, err = newGeolocationProvider(logger); err != nil {
+		return
+	}
+
 	return
 }
+
+func newGeolocationProvider(logger *zap.Logger) (chain.ChainProviderContract, error) {
+	providerConfigs, err := configurationService.GetGeolocationProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	consensus, err := configurationService.GetGeolocationProviderConsensus()
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, err := configurationService.GetGeolocationProviderRetryPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	circuitBreakerPolicy, err := configurationService.GetGeolocationProviderCircuitBreakerPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := configurationService.GetGeolocationProviderQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []chain.Provider{}
+
+	for _, providerConfig := range providerConfigs {
+		provider, err := newSingleGeolocationProvider(logger, providerConfig.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		providers = append(providers, chain.Provider{
+			Name:     providerConfig.Name,
+			Provider: provider,
+			Timeout:  providerConfig.Timeout,
+		})
+	}
+
+	return chain.NewChainProvider(logger, providers, consensus, retryPolicy, circuitBreakerPolicy, quotas, stateStore)
+}
+
+func newSingleGeolocationProvider(
+	logger *zap.Logger,
+	providerType configuration.GeolocationProviderType) (geolocation.GeolocationProviderContract, error) {
+	switch providerType {
+	case configuration.Ipinfo:
+		resolverType, err := configurationService.GetPublicIPResolver()
+		if err != nil {
+			return nil, err
+		}
+
+		// Ipinfo discovers the public IP address itself the same way the HTTP resolver does, so only the STUN and
+		// DNS resolvers, that reach the public IP address differently, are used to look it up
+		if resolverType == configuration.HttpPublicIPResolver {
+			return ipinfo.NewIpinfoProvider(logger, configurationService, nil)
+		}
+
+		publicIPResolver, err := newPublicIPResolver(logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return ipinfo.NewIpinfoProvider(logger, configurationService, publicIPResolver)
+	case configuration.MaxMind:
+		publicIPResolver, err := newPublicIPResolver(logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return maxmind.NewMaxMindProvider(logger, configurationService, publicIPResolver)
+	default:
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("geolocation provider %v is not supported", providerType))
+	}
+}
+
+func newPublicIPResolver(logger *zap.Logger) (publicip.PublicIPResolverContract, error) {
+	resolverType, err := configurationService.GetPublicIPResolver()
+	if err != nil {
+		return nil, err
+	}
+
+	switch resolverType {
+	case configuration.HttpPublicIPResolver:
+		return publicIPHttp.NewHttpResolver(logger, configurationService)
+	case configuration.StunPublicIPResolver:
+		return stun.NewStunResolver(logger, configurationService)
+	case configuration.DnsPublicIPResolver:
+		return dns.NewDnsResolver(logger, configurationService)
+	default:
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("public IP resolver %v is not supported", resolverType))
+	}
+}
@@ -22,4 +22,9 @@ docker create --name extract-mock-builder mock-builder
 docker cp extract-mock-builder:/src/services/transport/mock/mock-contract.go ./services/transport/mock/mock-contract.go
 docker cp extract-mock-builder:/src/services/cron/mock/mock-contract.go ./services/cron/mock/mock-contract.go
 docker cp extract-mock-builder:/src/services/configuration/mock/mock-contract.go ./services/configuration/mock/mock-contract.go
+docker cp extract-mock-builder:/src/services/geolocation/mock/mock-contract.go ./services/geolocation/mock/mock-contract.go
+docker cp extract-mock-builder:/src/services/publicip/mock/mock-contract.go ./services/publicip/mock/mock-contract.go
+docker cp extract-mock-builder:/src/services/state/mock/mock-contract.go ./services/state/mock/mock-contract.go
+docker cp extract-mock-builder:/src/services/cron/ipgeolocation/mock/mock-contract.go ./services/cron/ipgeolocation/mock/mock-contract.go
+docker cp extract-mock-builder:/src/services/geolocation/chain/mock/mock-contract.go ./services/geolocation/chain/mock/mock-contract.go
 
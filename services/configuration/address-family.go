@@ -0,0 +1,52 @@
+// Package configuration implements configuration service required by the edge-core service
+package configuration
+
+import "net"
+
+// AddressFamily is the IP address family used to reach the geolocation providers and public IP resolvers
+type AddressFamily int
+
+const (
+	// AnyAddressFamily lets the operating system pick the address family
+	AnyAddressFamily AddressFamily = iota
+	// IPv4 restricts the connections to IPv4
+	IPv4
+	// IPv6 restricts the connections to IPv6
+	IPv6
+)
+
+// String returns the name of the address family as used in configuration and node labels
+func (addressFamily AddressFamily) String() string {
+	switch addressFamily {
+	case IPv4:
+		return "ipv4"
+	case IPv6:
+		return "ipv6"
+	default:
+		return "any"
+	}
+}
+
+// Network returns the given network (tcp, udp or ip) restricted to the address family, e.g. tcp4 for tcp and IPv4
+func (addressFamily AddressFamily) Network(network string) string {
+	switch addressFamily {
+	case IPv4:
+		return network + "4"
+	case IPv6:
+		return network + "6"
+	default:
+		return network
+	}
+}
+
+// Matches returns true if the given IP address belongs to the address family
+func (addressFamily AddressFamily) Matches(ip net.IP) bool {
+	switch addressFamily {
+	case IPv4:
+		return ip.To4() != nil
+	case IPv6:
+		return ip.To4() == nil && ip.To16() != nil
+	default:
+		return ip != nil
+	}
+}
@@ -1,6 +1,8 @@
 // Package configuration implements configuration service required by the edge-core service
 package configuration
 
+import "time"
+
 // ClusterType is the edge cluster type
 type ClusterType int
 
@@ -11,6 +13,97 @@ const (
 	K3S
 )
 
+// GeolocationProviderType is the type of provider used to resolve the node public IP address and geolocation details
+type GeolocationProviderType int
+
+const (
+	// UnknownGeolocationProvider determines that configuration service could not determine the geolocation provider
+	UnknownGeolocationProvider GeolocationProviderType = iota
+	// Ipinfo is the geolocation provider that uses Ipinfo website
+	Ipinfo
+	// MaxMind is the geolocation provider that uses local MaxMind GeoLite2/GeoIP2 databases
+	MaxMind
+)
+
+// GeolocationProviderConfig contains the configuration of a single geolocation provider in the provider chain
+type GeolocationProviderConfig struct {
+	// Name is the name of the provider as configured, used in logs and recorded as the provenance of the result
+	Name string
+
+	// Type is the type of the provider
+	Type GeolocationProviderType
+
+	// Timeout is the maximum time allowed for a single call to the provider
+	Timeout time.Duration
+}
+
+// PublicIPResolverType is the type of resolver used to discover the node public IP address
+type PublicIPResolverType int
+
+const (
+	// UnknownPublicIPResolver determines that configuration service could not determine the public IP resolver
+	UnknownPublicIPResolver PublicIPResolverType = iota
+	// HttpPublicIPResolver is the public IP resolver that uses a plain text HTTP endpoint
+	HttpPublicIPResolver
+	// StunPublicIPResolver is the public IP resolver that uses STUN binding requests
+	StunPublicIPResolver
+	// DnsPublicIPResolver is the public IP resolver that uses myip-style DNS queries
+	DnsPublicIPResolver
+)
+
+// DnsPublicIPQuery contains a DNS query that returns the node public IP address
+type DnsPublicIPQuery struct {
+	// RecordType is the type of the DNS record to query, one of A, AAAA or TXT
+	RecordType string
+
+	// Name is the fully qualified name to query, e.g. myip.opendns.com
+	Name string
+
+	// Server is the DNS server in host:port format to send the query to, e.g. resolver1.opendns.com:53
+	Server string
+}
+
+// RetryPolicy contains how failed calls to a geolocation provider are retried
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt, zero disables retries
+	MaxRetries int
+
+	// InitialBackoff is the upper bound of the jittered wait before the first retry, doubled for every next retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum upper bound of the jittered wait between retries
+	MaxBackoff time.Duration
+}
+
+// SchedulePolicy contains how the geolocation update runs of the nodes are spread over time, so a large fleet does
+// not call the providers and the Kubernetes API server at the same second
+type SchedulePolicy struct {
+	// Spread is the window the deterministic per node offset derived from the node name falls in, zero disables it
+	Spread time.Duration
+
+	// Jitter is the upper bound of the random delay added to every run, zero disables it
+	Jitter time.Duration
+}
+
+// CircuitBreakerPolicy contains when the circuit breaker of a geolocation provider opens and for how long
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls after which the circuit breaker opens, zero
+	// disables the circuit breaker
+	FailureThreshold int
+
+	// OpenDuration is how long the circuit breaker stays open before a trial call is let through
+	OpenDuration time.Duration
+}
+
+// ProviderQuota contains the number of requests a geolocation provider may receive from the node in a period
+type ProviderQuota struct {
+	// Requests is the maximum number of requests in the period
+	Requests int
+
+	// Period is the length of the period the requests are counted in, e.g. 720h for a monthly quota
+	Period time.Duration
+}
+
 // ConfigurationContract declares the service that provides configuration required by different Tenat modules
 type ConfigurationContract interface {
 	// GetHttpHost returns HTTP host name
@@ -39,6 +132,16 @@ type ConfigurationContract interface {
 	// Returns the Geolocation Updater updating interval or error if something goes wrong
 	GetGeolocationUpdaterCronSpec() (string, error)
 
+	// GetGeolocationProviders returns the ordered list of providers to be used to resolve the node public IP address
+	// and geolocation details. The next provider is used if the previous one fails.
+	// Returns the ordered list of geolocation providers or error if something goes wrong
+	GetGeolocationProviders() ([]GeolocationProviderConfig, error)
+
+	// GetGeolocationProviderConsensus returns the minimum number of geolocation providers that must agree on
+	// the node public IP address before it is accepted. Values less than or equal to one disable the consensus mode.
+	// Returns the minimum number of providers that must agree or error if something goes wrong
+	GetGeolocationProviderConsensus() (int, error)
+
 	// GetIpinfoUrl returns the URL to the Ipinfo website that returns the node public IP address
 	// Returns the URL to the Ipinfo website that returns the node public IP address or error if something goes wrong
 	GetIpinfoUrl() (string, error)
@@ -48,4 +151,146 @@ type ConfigurationContract interface {
 	// Returns the access token to be used when making request to the Ipinfo website to return the node
 	// public IP address or error if something goes wrong
 	GetIpinfoAccessToken() (string, error)
+
+	// GetMaxMindCityDatabasePath returns the path to the MaxMind GeoLite2/GeoIP2 City database file
+	// Returns the path to the MaxMind City database file or error if something goes wrong
+	GetMaxMindCityDatabasePath() (string, error)
+
+	// GetMaxMindAsnDatabasePath returns the path to the optional MaxMind GeoLite2/GeoIP2 ASN database file
+	// Returns the path to the MaxMind ASN database file, or empty string if not set, or error if something goes wrong
+	GetMaxMindAsnDatabasePath() (string, error)
+
+	// GetPublicIPResolver returns the type of resolver to be used to discover the node public IP address
+	// when the geolocation provider does not discover it itself, or when the STUN or DNS resolver is configured
+	// for a provider that does
+	// Returns the type of public IP resolver or error if something goes wrong
+	GetPublicIPResolver() (PublicIPResolverType, error)
+
+	// GetPublicIPResolverUrl returns the URL to the HTTP endpoint that returns the node public IP address as plain text
+	// Returns the URL to the HTTP endpoint that returns the node public IP address or error if something goes wrong
+	GetPublicIPResolverUrl() (string, error)
+
+	// GetStunServers returns the list of STUN servers in host:port format to send binding requests to
+	// Returns the list of STUN servers or error if something goes wrong
+	GetStunServers() ([]string, error)
+
+	// GetDnsPublicIPQueries returns the ordered list of DNS queries to be used to discover the node public IP address
+	// Returns the ordered list of DNS queries or error if something goes wrong
+	GetDnsPublicIPQueries() ([]DnsPublicIPQuery, error)
+
+	// GetGeolocationAddressFamilies returns the ordered list of address families to probe the node public IP address
+	// and geolocation details for. The first family that succeeds provides the primary public IP address.
+	// Returns the ordered list of address families or error if something goes wrong
+	GetGeolocationAddressFamilies() ([]AddressFamily, error)
+
+	// ShouldUpdateNodeGeolocationResource determines whether the edge-core should maintain the NodeGeolocation
+	// custom resource of the node in addition to the node labels
+	// Returns true if the edge-core should maintain the NodeGeolocation custom resource otherwise returns false
+	ShouldUpdateNodeGeolocationResource() bool
+
+	// GetNodeGeolocationHistorySize returns the maximum number of previous locations kept in the NodeGeolocation
+	// custom resource status
+	// Returns the maximum number of previous locations or error if something goes wrong
+	GetNodeGeolocationHistorySize() (int, error)
+
+	// GetGeolocationFailureEventThreshold returns the number of consecutive failures to resolve the geolocation
+	// details or to patch the node after which Warning events are recorded against the node
+	// Returns the number of consecutive failures or error if something goes wrong
+	GetGeolocationFailureEventThreshold() (int, error)
+
+	// GetGeolocationHeartbeatInterval returns the minimum interval between updates of the last checked time label
+	// of the node and the last success time of its NodeGeolocation when the geolocation details did not change.
+	// Zero disables the heartbeat.
+	// Returns the heartbeat interval or error if something goes wrong
+	GetGeolocationHeartbeatInterval() (time.Duration, error)
+
+	// GetGeolocationUpdaterSchedulePolicy returns how the geolocation update runs of the nodes are spread over time
+	// Returns the schedule policy or error if something goes wrong
+	GetGeolocationUpdaterSchedulePolicy() (SchedulePolicy, error)
+
+	// GetGeolocationProviderRetryPolicy returns how failed calls to the geolocation providers are retried
+	// Returns the retry policy or error if something goes wrong
+	GetGeolocationProviderRetryPolicy() (RetryPolicy, error)
+
+	// GetGeolocationProviderCircuitBreakerPolicy returns when the circuit breaker of each geolocation provider opens
+	// Returns the circuit breaker policy or error if something goes wrong
+	GetGeolocationProviderCircuitBreakerPolicy() (CircuitBreakerPolicy, error)
+
+	// GetGeolocationProviderQuotas returns the request quota of the geolocation providers that have one
+	// Returns the request quotas keyed by the provider name or error if something goes wrong
+	GetGeolocationProviderQuotas() (map[string]ProviderQuota, error)
+
+	// GetGeolocationStateFilePath returns the path to the file the last known public IP address and geolocation
+	// details are saved to
+	// Returns the path to the state file or error if something goes wrong
+	GetGeolocationStateFilePath() (string, error)
+
+	// GetGeolocationStateFreshnessWindow returns how long the saved details are used instead of calling the
+	// geolocation providers. Zero always calls the providers.
+	// Returns the freshness window or error if something goes wrong
+	GetGeolocationStateFreshnessWindow() (time.Duration, error)
+
+	// ShouldUpdateTopologyLabels determines whether the edge-core should derive the well-known
+	// topology.kubernetes.io/region and topology.kubernetes.io/zone node labels from the geolocation details
+	// Returns true if the edge-core should update the topology labels otherwise returns false
+	ShouldUpdateTopologyLabels() bool
+
+	// GetTopologyRegionTemplate returns the template the topology.kubernetes.io/region label value is derived from,
+	// where {country}, {region}, {city} and {postal} are replaced with the geolocation details
+	// Returns the region template or error if something goes wrong
+	GetTopologyRegionTemplate() (string, error)
+
+	// GetTopologyZoneTemplate returns the template the topology.kubernetes.io/zone label value is derived from,
+	// where {country}, {region}, {city} and {postal} are replaced with the geolocation details
+	// Returns the zone template or error if something goes wrong
+	GetTopologyZoneTemplate() (string, error)
+
+	// GetGeohashPrecisions returns the precisions the geohash labels of the node location are computed at
+	// Returns the geohash precisions or error if something goes wrong
+	GetGeohashPrecisions() ([]int, error)
+
+	// GetMovementThreshold returns the great-circle distance in kilometers between the previous and the new location
+	// above which the node is considered moved. Zero disables movement detection.
+	// Returns the movement threshold or error if something goes wrong
+	GetMovementThreshold() (float64, error)
+
+	// ShouldHoldLabelsOnMovement determines whether the edge-core should stop updating the node labels once the node
+	// moved, until an operator acknowledges the movement through an annotation
+	// Returns true if the edge-core should hold the labels otherwise returns false
+	ShouldHoldLabelsOnMovement() bool
+
+	// GetAsnNetworkTypes returns the network type, one of residential, mobile or hosting, of the autonomous systems
+	// the node can be connected through
+	// Returns the network types keyed by the autonomous system number or error if something goes wrong
+	GetAsnNetworkTypes() (map[string]string, error)
+
+	// ShouldRefreshOnNetworkChange determines whether the edge-core should update the public IP address and
+	// geolocation details as soon as the default route or an address of a WAN interface of the node changes
+	// Returns true if the edge-core should refresh on network changes otherwise returns false
+	ShouldRefreshOnNetworkChange() bool
+
+	// GetNetworkChangeDebounce returns how long the edge-core waits for the network to settle after the last
+	// network change before updating the public IP address and geolocation details
+	// Returns the debounce interval or error if something goes wrong
+	GetNetworkChangeDebounce() (time.Duration, error)
+
+	// GetWanInterfaces returns the names of the node network interfaces whose address changes trigger a refresh.
+	// Empty means every interface except the loopback and the container network interfaces.
+	// Returns the WAN interface names
+	GetWanInterfaces() []string
+
+	// GetGeolocationRefreshTimeout returns how long a refresh requested through the HTTP endpoint waits for the
+	// geolocation update to finish
+	// Returns the refresh timeout or error if something goes wrong
+	GetGeolocationRefreshTimeout() (time.Duration, error)
+
+	// GetGeolocationRefreshMinInterval returns the minimum interval between the geolocation updates started by the
+	// refreshes requested through the HTTP endpoint. A refresh requested sooner returns the result of the latest
+	// update. Zero starts an update on every refresh.
+	// Returns the refresh minimum interval or error if something goes wrong
+	GetGeolocationRefreshMinInterval() (time.Duration, error)
+
+	// GetGeolocationRunHistorySize returns the number of the latest geolocation update run results kept in memory
+	// Returns the run history size or error if something goes wrong
+	GetGeolocationRunHistorySize() (int, error)
 }
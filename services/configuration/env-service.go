@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	commonErrors "github.com/micro-business/go-core/system/errors"
 )
@@ -90,6 +91,81 @@ func (service *envConfigurationService) GetGeolocationUpdaterCronSpec() (string,
 	return value, nil
 }
 
+// GetGeolocationProviders returns the ordered list of providers to be used to resolve the node public IP address
+// and geolocation details. The next provider is used if the previous one fails.
+// Returns the ordered list of geolocation providers or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationProviders() ([]GeolocationProviderConfig, error) {
+	defaultTimeout := 15 * time.Second
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDER_TIMEOUT"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return nil, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_PROVIDER_TIMEOUT to duration", err)
+		}
+
+		defaultTimeout = value
+	}
+
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDERS"), " ")
+	if valueStr == "" {
+		valueStr = "ipinfo"
+	}
+
+	providers := []GeolocationProviderConfig{}
+
+	for _, item := range strings.Split(valueStr, ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+
+		provider := GeolocationProviderConfig{Timeout: defaultTimeout}
+
+		// Each entry is either the provider name or the provider name and its timeout, e.g. ipinfo:10s
+		if index := strings.Index(item, ":"); index >= 0 {
+			timeout, err := time.ParseDuration(item[index+1:])
+			if err != nil {
+				return nil, commonErrors.NewUnknownErrorWithError(
+					fmt.Sprintf("Failed to convert the timeout of the geolocation provider (%s) to duration", item), err)
+			}
+
+			provider.Timeout = timeout
+			item = item[:index]
+		}
+
+		switch item {
+		case "ipinfo":
+			provider.Type = Ipinfo
+		case "maxmind":
+			provider.Type = MaxMind
+		default:
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not figure out the geolocation provider from the given GEOLOCATION_PROVIDERS (%s)", item))
+		}
+
+		provider.Name = item
+		providers = append(providers, provider)
+	}
+
+	return providers, nil
+}
+
+// GetGeolocationProviderConsensus returns the minimum number of geolocation providers that must agree on
+// the node public IP address before it is accepted. Values less than or equal to one disable the consensus mode.
+// Returns the minimum number of providers that must agree or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationProviderConsensus() (int, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDER_CONSENSUS"), " ")
+	if valueStr == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_PROVIDER_CONSENSUS to integer", err)
+	}
+
+	return value, nil
+}
+
 // GetIpinfoUrl returns the URL to the Ipinfo website that returns the node public IP address
 // Returns the URL to the Ipinfo website that returns the node public IP address or error if something goes wrong
 func (service *envConfigurationService) GetIpinfoUrl() (string, error) {
@@ -108,3 +184,637 @@ func (service *envConfigurationService) GetIpinfoUrl() (string, error) {
 func (service *envConfigurationService) GetIpinfoAccessToken() (string, error) {
 	return os.Getenv("IPINFO_ACCESS_TOKEN"), nil
 }
+
+// GetMaxMindCityDatabasePath returns the path to the MaxMind GeoLite2/GeoIP2 City database file
+// Returns the path to the MaxMind City database file or error if something goes wrong
+func (service *envConfigurationService) GetMaxMindCityDatabasePath() (string, error) {
+	value := os.Getenv("MAXMIND_CITY_DATABASE_PATH")
+	if strings.Trim(value, " ") == "" {
+		return "", commonErrors.NewUnknownError("MAXMIND_CITY_DATABASE_PATH is required")
+	}
+
+	return value, nil
+}
+
+// GetMaxMindAsnDatabasePath returns the path to the optional MaxMind GeoLite2/GeoIP2 ASN database file
+// Returns the path to the MaxMind ASN database file, or empty string if not set, or error if something goes wrong
+func (service *envConfigurationService) GetMaxMindAsnDatabasePath() (string, error) {
+	return os.Getenv("MAXMIND_ASN_DATABASE_PATH"), nil
+}
+
+// GetPublicIPResolver returns the type of resolver to be used to discover the node public IP address
+// when the geolocation provider does not discover it itself, or when the STUN or DNS resolver is configured
+// for a provider that does
+// Returns the type of public IP resolver or error if something goes wrong
+func (service *envConfigurationService) GetPublicIPResolver() (PublicIPResolverType, error) {
+	switch value := strings.Trim(os.Getenv("PUBLIC_IP_RESOLVER"), " "); value {
+	case "http", "":
+		return HttpPublicIPResolver, nil
+	case "stun":
+		return StunPublicIPResolver, nil
+	case "dns":
+		return DnsPublicIPResolver, nil
+	default:
+		return UnknownPublicIPResolver, commonErrors.NewUnknownError(
+			fmt.Sprintf("Could not figure out the public IP resolver from the given PUBLIC_IP_RESOLVER (%s)", value))
+	}
+}
+
+// GetPublicIPResolverUrl returns the URL to the HTTP endpoint that returns the node public IP address as plain text
+// Returns the URL to the HTTP endpoint that returns the node public IP address or error if something goes wrong
+func (service *envConfigurationService) GetPublicIPResolverUrl() (string, error) {
+	value := os.Getenv("PUBLIC_IP_RESOLVER_URL")
+	if strings.Trim(value, " ") == "" {
+		return "https://api.ipify.org", nil
+	}
+
+	return value, nil
+}
+
+// GetStunServers returns the list of STUN servers in host:port format to send binding requests to
+// Returns the list of STUN servers or error if something goes wrong
+func (service *envConfigurationService) GetStunServers() ([]string, error) {
+	valueStr := strings.Trim(os.Getenv("STUN_SERVERS"), " ")
+	if valueStr == "" {
+		valueStr = "stun.l.google.com:19302,stun1.l.google.com:19302"
+	}
+
+	servers := []string{}
+
+	for _, server := range strings.Split(valueStr, ",") {
+		if server = strings.Trim(server, " "); server != "" {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers, nil
+}
+
+// GetDnsPublicIPQueries returns the ordered list of DNS queries to be used to discover the node public IP address
+// Returns the ordered list of DNS queries or error if something goes wrong
+func (service *envConfigurationService) GetDnsPublicIPQueries() ([]DnsPublicIPQuery, error) {
+	valueStr := strings.Trim(os.Getenv("DNS_PUBLIC_IP_QUERIES"), " ")
+	if valueStr == "" {
+		valueStr = "A:myip.opendns.com@resolver1.opendns.com:53,AAAA:myip.opendns.com@resolver1.opendns.com:53," +
+			"TXT:o-o.myaddr.l.google.com@ns1.google.com:53"
+	}
+
+	queries := []DnsPublicIPQuery{}
+
+	// Each entry is in RECORD_TYPE:NAME@SERVER:PORT format
+	for _, item := range strings.Split(valueStr, ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+
+		typeIndex := strings.Index(item, ":")
+		serverIndex := strings.Index(item, "@")
+		if typeIndex <= 0 || serverIndex <= typeIndex+1 || serverIndex == len(item)-1 {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not parse the DNS query from the given DNS_PUBLIC_IP_QUERIES (%s)", item))
+		}
+
+		query := DnsPublicIPQuery{
+			RecordType: strings.ToUpper(item[:typeIndex]),
+			Name:       item[typeIndex+1 : serverIndex],
+			Server:     item[serverIndex+1:],
+		}
+
+		switch query.RecordType {
+		case "A", "AAAA", "TXT":
+		default:
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Unsupported DNS record type in the given DNS_PUBLIC_IP_QUERIES (%s)", item))
+		}
+
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+// GetGeolocationAddressFamilies returns the ordered list of address families to probe the node public IP address
+// and geolocation details for. The first family that succeeds provides the primary public IP address.
+// Returns the ordered list of address families or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationAddressFamilies() ([]AddressFamily, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_ADDRESS_FAMILIES"), " ")
+	if valueStr == "" {
+		valueStr = "ipv4,ipv6"
+	}
+
+	addressFamilies := []AddressFamily{}
+
+	for _, item := range strings.Split(valueStr, ",") {
+		switch item = strings.Trim(item, " "); item {
+		case "":
+			continue
+		case "any":
+			addressFamilies = append(addressFamilies, AnyAddressFamily)
+		case "ipv4":
+			addressFamilies = append(addressFamilies, IPv4)
+		case "ipv6":
+			addressFamilies = append(addressFamilies, IPv6)
+		default:
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not figure out the address family from the given GEOLOCATION_ADDRESS_FAMILIES (%s)", item))
+		}
+	}
+
+	return addressFamilies, nil
+}
+
+// ShouldUpdateNodeGeolocationResource determines whether the edge-core should maintain the NodeGeolocation
+// custom resource of the node in addition to the node labels
+// Returns true if the edge-core should maintain the NodeGeolocation custom resource otherwise returns false
+func (service *envConfigurationService) ShouldUpdateNodeGeolocationResource() bool {
+	if value := strings.Trim(os.Getenv("UPDATE_NODE_GEOLOCATION_RESOURCE"), " "); value == "true" {
+		return true
+	}
+
+	return false
+}
+
+// GetNodeGeolocationHistorySize returns the maximum number of previous locations kept in the NodeGeolocation
+// custom resource status
+// Returns the maximum number of previous locations or error if something goes wrong
+func (service *envConfigurationService) GetNodeGeolocationHistorySize() (int, error) {
+	valueStr := strings.Trim(os.Getenv("NODE_GEOLOCATION_HISTORY_SIZE"), " ")
+	if valueStr == "" {
+		return 10, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert NODE_GEOLOCATION_HISTORY_SIZE to integer", err)
+	}
+
+	if value < 0 {
+		return 0, commonErrors.NewUnknownError("NODE_GEOLOCATION_HISTORY_SIZE can not be negative")
+	}
+
+	return value, nil
+}
+
+// GetGeolocationFailureEventThreshold returns the number of consecutive failures to resolve the geolocation
+// details or to patch the node after which Warning events are recorded against the node
+// Returns the number of consecutive failures or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationFailureEventThreshold() (int, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_FAILURE_EVENT_THRESHOLD"), " ")
+	if valueStr == "" {
+		return 3, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_FAILURE_EVENT_THRESHOLD to integer", err)
+	}
+
+	if value < 1 {
+		return 0, commonErrors.NewUnknownError("GEOLOCATION_FAILURE_EVENT_THRESHOLD must be at least 1")
+	}
+
+	return value, nil
+}
+
+// GetGeolocationHeartbeatInterval returns the minimum interval between updates of the last checked time label
+// of the node and the last success time of its NodeGeolocation when the geolocation details did not change.
+// Zero disables the heartbeat.
+// Returns the heartbeat interval or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationHeartbeatInterval() (time.Duration, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_HEARTBEAT_INTERVAL"), " ")
+	if valueStr == "" {
+		return 0, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_HEARTBEAT_INTERVAL to duration", err)
+	}
+
+	return value, nil
+}
+
+// GetGeolocationUpdaterSchedulePolicy returns how the geolocation update runs of the nodes are spread over time
+// Returns the schedule policy or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationUpdaterSchedulePolicy() (SchedulePolicy, error) {
+	schedulePolicy := SchedulePolicy{}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_UPDATER_SPREAD"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return SchedulePolicy{}, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_UPDATER_SPREAD to duration", err)
+		}
+
+		if value < 0 {
+			return SchedulePolicy{}, commonErrors.NewUnknownError("GEOLOCATION_UPDATER_SPREAD can not be negative")
+		}
+
+		schedulePolicy.Spread = value
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_UPDATER_JITTER"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return SchedulePolicy{}, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_UPDATER_JITTER to duration", err)
+		}
+
+		if value < 0 {
+			return SchedulePolicy{}, commonErrors.NewUnknownError("GEOLOCATION_UPDATER_JITTER can not be negative")
+		}
+
+		schedulePolicy.Jitter = value
+	}
+
+	return schedulePolicy, nil
+}
+
+// GetGeolocationProviderRetryPolicy returns how failed calls to the geolocation providers are retried
+// Returns the retry policy or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationProviderRetryPolicy() (RetryPolicy, error) {
+	retryPolicy := RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDER_MAX_RETRIES"), " "); valueStr != "" {
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return RetryPolicy{}, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_PROVIDER_MAX_RETRIES to integer", err)
+		}
+
+		if value < 0 {
+			return RetryPolicy{}, commonErrors.NewUnknownError("GEOLOCATION_PROVIDER_MAX_RETRIES can not be negative")
+		}
+
+		retryPolicy.MaxRetries = value
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDER_INITIAL_BACKOFF"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return RetryPolicy{}, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_PROVIDER_INITIAL_BACKOFF to duration", err)
+		}
+
+		retryPolicy.InitialBackoff = value
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_PROVIDER_MAX_BACKOFF"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return RetryPolicy{}, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_PROVIDER_MAX_BACKOFF to duration", err)
+		}
+
+		retryPolicy.MaxBackoff = value
+	}
+
+	return retryPolicy, nil
+}
+
+// GetGeolocationProviderCircuitBreakerPolicy returns when the circuit breaker of each geolocation provider opens
+// Returns the circuit breaker policy or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationProviderCircuitBreakerPolicy() (CircuitBreakerPolicy, error) {
+	circuitBreakerPolicy := CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		OpenDuration:     5 * time.Minute,
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_CIRCUIT_BREAKER_FAILURE_THRESHOLD"), " "); valueStr != "" {
+		value, err := strconv.Atoi(valueStr)
+		if err != nil {
+			return CircuitBreakerPolicy{}, commonErrors.NewUnknownErrorWithError(
+				"Failed to convert GEOLOCATION_CIRCUIT_BREAKER_FAILURE_THRESHOLD to integer", err)
+		}
+
+		if value < 0 {
+			return CircuitBreakerPolicy{}, commonErrors.NewUnknownError("GEOLOCATION_CIRCUIT_BREAKER_FAILURE_THRESHOLD can not be negative")
+		}
+
+		circuitBreakerPolicy.FailureThreshold = value
+	}
+
+	if valueStr := strings.Trim(os.Getenv("GEOLOCATION_CIRCUIT_BREAKER_OPEN_DURATION"), " "); valueStr != "" {
+		value, err := time.ParseDuration(valueStr)
+		if err != nil {
+			return CircuitBreakerPolicy{}, commonErrors.NewUnknownErrorWithError(
+				"Failed to convert GEOLOCATION_CIRCUIT_BREAKER_OPEN_DURATION to duration", err)
+		}
+
+		circuitBreakerPolicy.OpenDuration = value
+	}
+
+	return circuitBreakerPolicy, nil
+}
+
+// GetGeolocationProviderQuotas returns the request quota of the geolocation providers that have one
+// Returns the request quotas keyed by the provider name or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationProviderQuotas() (map[string]ProviderQuota, error) {
+	quotas := map[string]ProviderQuota{}
+
+	// Each entry is in NAME:REQUESTS/PERIOD format, e.g. ipinfo:1000/720h
+	for _, item := range strings.Split(os.Getenv("GEOLOCATION_PROVIDER_QUOTAS"), ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+
+		nameIndex := strings.Index(item, ":")
+		periodIndex := strings.LastIndex(item, "/")
+		if nameIndex <= 0 || periodIndex < nameIndex {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not parse the quota from the given GEOLOCATION_PROVIDER_QUOTAS (%s)", item))
+		}
+
+		requests, err := strconv.Atoi(item[nameIndex+1 : periodIndex])
+		if err != nil || requests <= 0 {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not parse the number of requests from the given GEOLOCATION_PROVIDER_QUOTAS (%s)", item))
+		}
+
+		period, err := time.ParseDuration(item[periodIndex+1:])
+		if err != nil || period <= 0 {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not parse the period from the given GEOLOCATION_PROVIDER_QUOTAS (%s)", item))
+		}
+
+		quotas[item[:nameIndex]] = ProviderQuota{Requests: requests, Period: period}
+	}
+
+	return quotas, nil
+}
+
+// GetGeolocationStateFilePath returns the path to the file the last known public IP address and geolocation
+// details are saved to
+// Returns the path to the state file or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationStateFilePath() (string, error) {
+	value := os.Getenv("GEOLOCATION_STATE_FILE_PATH")
+	if strings.Trim(value, " ") == "" {
+		return "/var/lib/edge-core/state/geolocation.json", nil
+	}
+
+	return value, nil
+}
+
+// GetGeolocationStateFreshnessWindow returns how long the saved details are used instead of calling the
+// geolocation providers. Zero always calls the providers.
+// Returns the freshness window or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationStateFreshnessWindow() (time.Duration, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_STATE_FRESHNESS_WINDOW"), " ")
+	if valueStr == "" {
+		return 0, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_STATE_FRESHNESS_WINDOW to duration", err)
+	}
+
+	return value, nil
+}
+
+// ShouldUpdateTopologyLabels determines whether the edge-core should derive the well-known
+// topology.kubernetes.io/region and topology.kubernetes.io/zone node labels from the geolocation details
+// Returns true if the edge-core should update the topology labels otherwise returns false
+func (service *envConfigurationService) ShouldUpdateTopologyLabels() bool {
+	if value := strings.Trim(os.Getenv("UPDATE_TOPOLOGY_LABELS"), " "); value == "true" {
+		return true
+	}
+
+	return false
+}
+
+// GetTopologyRegionTemplate returns the template the topology.kubernetes.io/region label value is derived from,
+// where {country}, {region}, {city} and {postal} are replaced with the geolocation details
+// Returns the region template or error if something goes wrong
+func (service *envConfigurationService) GetTopologyRegionTemplate() (string, error) {
+	value := os.Getenv("TOPOLOGY_REGION_TEMPLATE")
+	if strings.Trim(value, " ") == "" {
+		return "{country}-{region}", nil
+	}
+
+	return value, nil
+}
+
+// GetTopologyZoneTemplate returns the template the topology.kubernetes.io/zone label value is derived from,
+// where {country}, {region}, {city} and {postal} are replaced with the geolocation details
+// Returns the zone template or error if something goes wrong
+func (service *envConfigurationService) GetTopologyZoneTemplate() (string, error) {
+	value := os.Getenv("TOPOLOGY_ZONE_TEMPLATE")
+	if strings.Trim(value, " ") == "" {
+		return "{country}-{region}-{city}", nil
+	}
+
+	return value, nil
+}
+
+// GetGeohashPrecisions returns the precisions the geohash labels of the node location are computed at
+// Returns the geohash precisions or error if something goes wrong
+func (service *envConfigurationService) GetGeohashPrecisions() ([]int, error) {
+	valueStr := strings.Trim(os.Getenv("GEOHASH_PRECISIONS"), " ")
+	if valueStr == "" {
+		valueStr = "4,6"
+	}
+
+	precisions := []int{}
+
+	for _, item := range strings.Split(valueStr, ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("Failed to convert the geohash precision (%s) to integer", item), err)
+		}
+
+		if value < 1 || value > 12 {
+			return nil, commonErrors.NewUnknownError(fmt.Sprintf("Geohash precision (%d) must be between 1 and 12", value))
+		}
+
+		precisions = append(precisions, value)
+	}
+
+	return precisions, nil
+}
+
+// GetMovementThreshold returns the great-circle distance in kilometers between the previous and the new location
+// above which the node is considered moved. Zero disables movement detection.
+// Returns the movement threshold or error if something goes wrong
+func (service *envConfigurationService) GetMovementThreshold() (float64, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_MOVEMENT_THRESHOLD"), " ")
+	if valueStr == "" {
+		return 100, nil
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_MOVEMENT_THRESHOLD to number", err)
+	}
+
+	if value < 0 {
+		return 0, commonErrors.NewUnknownError("GEOLOCATION_MOVEMENT_THRESHOLD must not be negative")
+	}
+
+	return value, nil
+}
+
+// ShouldHoldLabelsOnMovement determines whether the edge-core should stop updating the node labels once the node
+// moved, until an operator acknowledges the movement through an annotation
+// Returns true if the edge-core should hold the labels otherwise returns false
+func (service *envConfigurationService) ShouldHoldLabelsOnMovement() bool {
+	if value := strings.Trim(os.Getenv("GEOLOCATION_HOLD_ON_MOVEMENT"), " "); value == "true" {
+		return true
+	}
+
+	return false
+}
+
+// GetAsnNetworkTypes returns the network type, one of residential, mobile or hosting, of the autonomous systems
+// the node can be connected through
+// Returns the network types keyed by the autonomous system number or error if something goes wrong
+func (service *envConfigurationService) GetAsnNetworkTypes() (map[string]string, error) {
+	networkTypes := map[string]string{}
+
+	// Each entry is in ASN:TYPE format, e.g. 13335:hosting
+	for _, item := range strings.Split(os.Getenv("GEOLOCATION_ASN_NETWORK_TYPES"), ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Could not parse the network type from the given GEOLOCATION_ASN_NETWORK_TYPES (%s)", item))
+		}
+
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(strings.Trim(parts[0], " ")), "AS"), 10, 32)
+		if err != nil {
+			return nil, commonErrors.NewUnknownErrorWithError(
+				fmt.Sprintf("Could not parse the autonomous system number from the given GEOLOCATION_ASN_NETWORK_TYPES (%s)", item),
+				err)
+		}
+
+		networkType := strings.ToLower(strings.Trim(parts[1], " "))
+		if networkType != "residential" && networkType != "mobile" && networkType != "hosting" {
+			return nil, commonErrors.NewUnknownError(
+				fmt.Sprintf("Network type in the given GEOLOCATION_ASN_NETWORK_TYPES (%s) must be residential, mobile or hosting", item))
+		}
+
+		networkTypes[strconv.FormatUint(asn, 10)] = networkType
+	}
+
+	return networkTypes, nil
+}
+
+// ShouldRefreshOnNetworkChange determines whether the edge-core should update the public IP address and
+// geolocation details as soon as the default route or an address of a WAN interface of the node changes
+// Returns true if the edge-core should refresh on network changes otherwise returns false
+func (service *envConfigurationService) ShouldRefreshOnNetworkChange() bool {
+	if value := strings.Trim(os.Getenv("REFRESH_ON_NETWORK_CHANGE"), " "); value == "true" {
+		return true
+	}
+
+	return false
+}
+
+// GetNetworkChangeDebounce returns how long the edge-core waits for the network to settle after the last
+// network change before updating the public IP address and geolocation details
+// Returns the debounce interval or error if something goes wrong
+func (service *envConfigurationService) GetNetworkChangeDebounce() (time.Duration, error) {
+	valueStr := strings.Trim(os.Getenv("NETWORK_CHANGE_DEBOUNCE"), " ")
+	if valueStr == "" {
+		return 10 * time.Second, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert NETWORK_CHANGE_DEBOUNCE to duration", err)
+	}
+
+	if value < 0 {
+		return 0, commonErrors.NewUnknownError("NETWORK_CHANGE_DEBOUNCE can not be negative")
+	}
+
+	return value, nil
+}
+
+// GetWanInterfaces returns the names of the node network interfaces whose address changes trigger a refresh.
+// Empty means every interface except the loopback and the container network interfaces.
+// Returns the WAN interface names
+func (service *envConfigurationService) GetWanInterfaces() []string {
+	wanInterfaces := []string{}
+
+	for _, item := range strings.Split(os.Getenv("WAN_INTERFACES"), ",") {
+		if item = strings.Trim(item, " "); item != "" {
+			wanInterfaces = append(wanInterfaces, item)
+		}
+	}
+
+	return wanInterfaces
+}
+
+// GetGeolocationRefreshTimeout returns how long a refresh requested through the HTTP endpoint waits for the
+// geolocation update to finish
+// Returns the refresh timeout or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationRefreshTimeout() (time.Duration, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_REFRESH_TIMEOUT"), " ")
+	if valueStr == "" {
+		return 90 * time.Second, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_REFRESH_TIMEOUT to duration", err)
+	}
+
+	if value <= 0 {
+		return 0, commonErrors.NewUnknownError("GEOLOCATION_REFRESH_TIMEOUT must be positive")
+	}
+
+	return value, nil
+}
+
+// GetGeolocationRefreshMinInterval returns the minimum interval between the geolocation updates started by the
+// refreshes requested through the HTTP endpoint. A refresh requested sooner returns the result of the latest
+// update. Zero starts an update on every refresh.
+// Returns the refresh minimum interval or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationRefreshMinInterval() (time.Duration, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_REFRESH_MIN_INTERVAL"), " ")
+	if valueStr == "" {
+		return time.Minute, nil
+	}
+
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_REFRESH_MIN_INTERVAL to duration", err)
+	}
+
+	if value < 0 {
+		return 0, commonErrors.NewUnknownError("GEOLOCATION_REFRESH_MIN_INTERVAL can not be negative")
+	}
+
+	return value, nil
+}
+
+// GetGeolocationRunHistorySize returns the number of the latest geolocation update run results kept in memory
+// Returns the run history size or error if something goes wrong
+func (service *envConfigurationService) GetGeolocationRunHistorySize() (int, error) {
+	valueStr := strings.Trim(os.Getenv("GEOLOCATION_RUN_HISTORY_SIZE"), " ")
+	if valueStr == "" {
+		return 20, nil
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return 0, commonErrors.NewUnknownErrorWithError("Failed to convert GEOLOCATION_RUN_HISTORY_SIZE to integer", err)
+	}
+
+	if value < 0 {
+		return 0, commonErrors.NewUnknownError("GEOLOCATION_RUN_HISTORY_SIZE can not be negative")
+	}
+
+	return value, nil
+}
@@ -6,6 +6,7 @@ package mock_configuration
 
 import (
 	reflect "reflect"
+	time "time"
 
 	configuration "github.com/decentralized-cloud/edge-core/services/configuration"
 	gomock "github.com/golang/mock/gomock"
@@ -34,6 +35,36 @@ func (m *MockConfigurationContract) EXPECT() *MockConfigurationContractMockRecor
 	return m.recorder
 }
 
+// GetAsnNetworkTypes mocks base method.
+func (m *MockConfigurationContract) GetAsnNetworkTypes() (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAsnNetworkTypes")
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAsnNetworkTypes indicates an expected call of GetAsnNetworkTypes.
+func (mr *MockConfigurationContractMockRecorder) GetAsnNetworkTypes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAsnNetworkTypes", reflect.TypeOf((*MockConfigurationContract)(nil).GetAsnNetworkTypes))
+}
+
+// GetDnsPublicIPQueries mocks base method.
+func (m *MockConfigurationContract) GetDnsPublicIPQueries() ([]configuration.DnsPublicIPQuery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDnsPublicIPQueries")
+	ret0, _ := ret[0].([]configuration.DnsPublicIPQuery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDnsPublicIPQueries indicates an expected call of GetDnsPublicIPQueries.
+func (mr *MockConfigurationContractMockRecorder) GetDnsPublicIPQueries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDnsPublicIPQueries", reflect.TypeOf((*MockConfigurationContract)(nil).GetDnsPublicIPQueries))
+}
+
 // GetEdgeClusterType mocks base method.
 func (m *MockConfigurationContract) GetEdgeClusterType() (configuration.ClusterType, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +80,216 @@ func (mr *MockConfigurationContractMockRecorder) GetEdgeClusterType() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEdgeClusterType", reflect.TypeOf((*MockConfigurationContract)(nil).GetEdgeClusterType))
 }
 
+// GetGeohashPrecisions mocks base method.
+func (m *MockConfigurationContract) GetGeohashPrecisions() ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeohashPrecisions")
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeohashPrecisions indicates an expected call of GetGeohashPrecisions.
+func (mr *MockConfigurationContractMockRecorder) GetGeohashPrecisions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeohashPrecisions", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeohashPrecisions))
+}
+
+// GetGeolocationAddressFamilies mocks base method.
+func (m *MockConfigurationContract) GetGeolocationAddressFamilies() ([]configuration.AddressFamily, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationAddressFamilies")
+	ret0, _ := ret[0].([]configuration.AddressFamily)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationAddressFamilies indicates an expected call of GetGeolocationAddressFamilies.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationAddressFamilies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationAddressFamilies", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationAddressFamilies))
+}
+
+// GetGeolocationFailureEventThreshold mocks base method.
+func (m *MockConfigurationContract) GetGeolocationFailureEventThreshold() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationFailureEventThreshold")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationFailureEventThreshold indicates an expected call of GetGeolocationFailureEventThreshold.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationFailureEventThreshold() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationFailureEventThreshold", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationFailureEventThreshold))
+}
+
+// GetGeolocationHeartbeatInterval mocks base method.
+func (m *MockConfigurationContract) GetGeolocationHeartbeatInterval() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationHeartbeatInterval")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationHeartbeatInterval indicates an expected call of GetGeolocationHeartbeatInterval.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationHeartbeatInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationHeartbeatInterval", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationHeartbeatInterval))
+}
+
+// GetGeolocationProviderCircuitBreakerPolicy mocks base method.
+func (m *MockConfigurationContract) GetGeolocationProviderCircuitBreakerPolicy() (configuration.CircuitBreakerPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationProviderCircuitBreakerPolicy")
+	ret0, _ := ret[0].(configuration.CircuitBreakerPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationProviderCircuitBreakerPolicy indicates an expected call of GetGeolocationProviderCircuitBreakerPolicy.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationProviderCircuitBreakerPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationProviderCircuitBreakerPolicy", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationProviderCircuitBreakerPolicy))
+}
+
+// GetGeolocationProviderConsensus mocks base method.
+func (m *MockConfigurationContract) GetGeolocationProviderConsensus() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationProviderConsensus")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationProviderConsensus indicates an expected call of GetGeolocationProviderConsensus.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationProviderConsensus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationProviderConsensus", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationProviderConsensus))
+}
+
+// GetGeolocationProviderQuotas mocks base method.
+func (m *MockConfigurationContract) GetGeolocationProviderQuotas() (map[string]configuration.ProviderQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationProviderQuotas")
+	ret0, _ := ret[0].(map[string]configuration.ProviderQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationProviderQuotas indicates an expected call of GetGeolocationProviderQuotas.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationProviderQuotas() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationProviderQuotas", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationProviderQuotas))
+}
+
+// GetGeolocationProviderRetryPolicy mocks base method.
+func (m *MockConfigurationContract) GetGeolocationProviderRetryPolicy() (configuration.RetryPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationProviderRetryPolicy")
+	ret0, _ := ret[0].(configuration.RetryPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationProviderRetryPolicy indicates an expected call of GetGeolocationProviderRetryPolicy.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationProviderRetryPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationProviderRetryPolicy", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationProviderRetryPolicy))
+}
+
+// GetGeolocationProviders mocks base method.
+func (m *MockConfigurationContract) GetGeolocationProviders() ([]configuration.GeolocationProviderConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationProviders")
+	ret0, _ := ret[0].([]configuration.GeolocationProviderConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationProviders indicates an expected call of GetGeolocationProviders.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationProviders", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationProviders))
+}
+
+// GetGeolocationRefreshMinInterval mocks base method.
+func (m *MockConfigurationContract) GetGeolocationRefreshMinInterval() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationRefreshMinInterval")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationRefreshMinInterval indicates an expected call of GetGeolocationRefreshMinInterval.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationRefreshMinInterval() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationRefreshMinInterval", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationRefreshMinInterval))
+}
+
+// GetGeolocationRefreshTimeout mocks base method.
+func (m *MockConfigurationContract) GetGeolocationRefreshTimeout() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationRefreshTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationRefreshTimeout indicates an expected call of GetGeolocationRefreshTimeout.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationRefreshTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationRefreshTimeout", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationRefreshTimeout))
+}
+
+// GetGeolocationRunHistorySize mocks base method.
+func (m *MockConfigurationContract) GetGeolocationRunHistorySize() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationRunHistorySize")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationRunHistorySize indicates an expected call of GetGeolocationRunHistorySize.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationRunHistorySize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationRunHistorySize", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationRunHistorySize))
+}
+
+// GetGeolocationStateFilePath mocks base method.
+func (m *MockConfigurationContract) GetGeolocationStateFilePath() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationStateFilePath")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationStateFilePath indicates an expected call of GetGeolocationStateFilePath.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationStateFilePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationStateFilePath", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationStateFilePath))
+}
+
+// GetGeolocationStateFreshnessWindow mocks base method.
+func (m *MockConfigurationContract) GetGeolocationStateFreshnessWindow() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationStateFreshnessWindow")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationStateFreshnessWindow indicates an expected call of GetGeolocationStateFreshnessWindow.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationStateFreshnessWindow() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationStateFreshnessWindow", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationStateFreshnessWindow))
+}
+
 // GetGeolocationUpdaterCronSpec mocks base method.
 func (m *MockConfigurationContract) GetGeolocationUpdaterCronSpec() (string, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +305,21 @@ func (mr *MockConfigurationContractMockRecorder) GetGeolocationUpdaterCronSpec()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationUpdaterCronSpec", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationUpdaterCronSpec))
 }
 
+// GetGeolocationUpdaterSchedulePolicy mocks base method.
+func (m *MockConfigurationContract) GetGeolocationUpdaterSchedulePolicy() (configuration.SchedulePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationUpdaterSchedulePolicy")
+	ret0, _ := ret[0].(configuration.SchedulePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationUpdaterSchedulePolicy indicates an expected call of GetGeolocationUpdaterSchedulePolicy.
+func (mr *MockConfigurationContractMockRecorder) GetGeolocationUpdaterSchedulePolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationUpdaterSchedulePolicy", reflect.TypeOf((*MockConfigurationContract)(nil).GetGeolocationUpdaterSchedulePolicy))
+}
+
 // GetHttpHost mocks base method.
 func (m *MockConfigurationContract) GetHttpHost() string {
 	m.ctrl.T.Helper()
@@ -123,6 +379,111 @@ func (mr *MockConfigurationContractMockRecorder) GetIpinfoUrl() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIpinfoUrl", reflect.TypeOf((*MockConfigurationContract)(nil).GetIpinfoUrl))
 }
 
+// GetMaxMindAsnDatabasePath mocks base method.
+func (m *MockConfigurationContract) GetMaxMindAsnDatabasePath() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxMindAsnDatabasePath")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxMindAsnDatabasePath indicates an expected call of GetMaxMindAsnDatabasePath.
+func (mr *MockConfigurationContractMockRecorder) GetMaxMindAsnDatabasePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxMindAsnDatabasePath", reflect.TypeOf((*MockConfigurationContract)(nil).GetMaxMindAsnDatabasePath))
+}
+
+// GetMaxMindCityDatabasePath mocks base method.
+func (m *MockConfigurationContract) GetMaxMindCityDatabasePath() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMaxMindCityDatabasePath")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMaxMindCityDatabasePath indicates an expected call of GetMaxMindCityDatabasePath.
+func (mr *MockConfigurationContractMockRecorder) GetMaxMindCityDatabasePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxMindCityDatabasePath", reflect.TypeOf((*MockConfigurationContract)(nil).GetMaxMindCityDatabasePath))
+}
+
+// GetMovementThreshold mocks base method.
+func (m *MockConfigurationContract) GetMovementThreshold() (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovementThreshold")
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovementThreshold indicates an expected call of GetMovementThreshold.
+func (mr *MockConfigurationContractMockRecorder) GetMovementThreshold() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovementThreshold", reflect.TypeOf((*MockConfigurationContract)(nil).GetMovementThreshold))
+}
+
+// GetNetworkChangeDebounce mocks base method.
+func (m *MockConfigurationContract) GetNetworkChangeDebounce() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetworkChangeDebounce")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetworkChangeDebounce indicates an expected call of GetNetworkChangeDebounce.
+func (mr *MockConfigurationContractMockRecorder) GetNetworkChangeDebounce() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetworkChangeDebounce", reflect.TypeOf((*MockConfigurationContract)(nil).GetNetworkChangeDebounce))
+}
+
+// GetNodeGeolocationHistorySize mocks base method.
+func (m *MockConfigurationContract) GetNodeGeolocationHistorySize() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeGeolocationHistorySize")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeGeolocationHistorySize indicates an expected call of GetNodeGeolocationHistorySize.
+func (mr *MockConfigurationContractMockRecorder) GetNodeGeolocationHistorySize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeGeolocationHistorySize", reflect.TypeOf((*MockConfigurationContract)(nil).GetNodeGeolocationHistorySize))
+}
+
+// GetPublicIPResolver mocks base method.
+func (m *MockConfigurationContract) GetPublicIPResolver() (configuration.PublicIPResolverType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicIPResolver")
+	ret0, _ := ret[0].(configuration.PublicIPResolverType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicIPResolver indicates an expected call of GetPublicIPResolver.
+func (mr *MockConfigurationContractMockRecorder) GetPublicIPResolver() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicIPResolver", reflect.TypeOf((*MockConfigurationContract)(nil).GetPublicIPResolver))
+}
+
+// GetPublicIPResolverUrl mocks base method.
+func (m *MockConfigurationContract) GetPublicIPResolverUrl() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicIPResolverUrl")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicIPResolverUrl indicates an expected call of GetPublicIPResolverUrl.
+func (mr *MockConfigurationContractMockRecorder) GetPublicIPResolverUrl() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicIPResolverUrl", reflect.TypeOf((*MockConfigurationContract)(nil).GetPublicIPResolverUrl))
+}
+
 // GetRunningNodeName mocks base method.
 func (m *MockConfigurationContract) GetRunningNodeName() (string, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +499,107 @@ func (mr *MockConfigurationContractMockRecorder) GetRunningNodeName() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunningNodeName", reflect.TypeOf((*MockConfigurationContract)(nil).GetRunningNodeName))
 }
 
+// GetStunServers mocks base method.
+func (m *MockConfigurationContract) GetStunServers() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStunServers")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStunServers indicates an expected call of GetStunServers.
+func (mr *MockConfigurationContractMockRecorder) GetStunServers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStunServers", reflect.TypeOf((*MockConfigurationContract)(nil).GetStunServers))
+}
+
+// GetTopologyRegionTemplate mocks base method.
+func (m *MockConfigurationContract) GetTopologyRegionTemplate() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopologyRegionTemplate")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopologyRegionTemplate indicates an expected call of GetTopologyRegionTemplate.
+func (mr *MockConfigurationContractMockRecorder) GetTopologyRegionTemplate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopologyRegionTemplate", reflect.TypeOf((*MockConfigurationContract)(nil).GetTopologyRegionTemplate))
+}
+
+// GetTopologyZoneTemplate mocks base method.
+func (m *MockConfigurationContract) GetTopologyZoneTemplate() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopologyZoneTemplate")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopologyZoneTemplate indicates an expected call of GetTopologyZoneTemplate.
+func (mr *MockConfigurationContractMockRecorder) GetTopologyZoneTemplate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopologyZoneTemplate", reflect.TypeOf((*MockConfigurationContract)(nil).GetTopologyZoneTemplate))
+}
+
+// GetWanInterfaces mocks base method.
+func (m *MockConfigurationContract) GetWanInterfaces() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWanInterfaces")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// GetWanInterfaces indicates an expected call of GetWanInterfaces.
+func (mr *MockConfigurationContractMockRecorder) GetWanInterfaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWanInterfaces", reflect.TypeOf((*MockConfigurationContract)(nil).GetWanInterfaces))
+}
+
+// ShouldHoldLabelsOnMovement mocks base method.
+func (m *MockConfigurationContract) ShouldHoldLabelsOnMovement() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldHoldLabelsOnMovement")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShouldHoldLabelsOnMovement indicates an expected call of ShouldHoldLabelsOnMovement.
+func (mr *MockConfigurationContractMockRecorder) ShouldHoldLabelsOnMovement() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldHoldLabelsOnMovement", reflect.TypeOf((*MockConfigurationContract)(nil).ShouldHoldLabelsOnMovement))
+}
+
+// ShouldRefreshOnNetworkChange mocks base method.
+func (m *MockConfigurationContract) ShouldRefreshOnNetworkChange() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldRefreshOnNetworkChange")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShouldRefreshOnNetworkChange indicates an expected call of ShouldRefreshOnNetworkChange.
+func (mr *MockConfigurationContractMockRecorder) ShouldRefreshOnNetworkChange() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldRefreshOnNetworkChange", reflect.TypeOf((*MockConfigurationContract)(nil).ShouldRefreshOnNetworkChange))
+}
+
+// ShouldUpdateNodeGeolocationResource mocks base method.
+func (m *MockConfigurationContract) ShouldUpdateNodeGeolocationResource() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldUpdateNodeGeolocationResource")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShouldUpdateNodeGeolocationResource indicates an expected call of ShouldUpdateNodeGeolocationResource.
+func (mr *MockConfigurationContractMockRecorder) ShouldUpdateNodeGeolocationResource() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldUpdateNodeGeolocationResource", reflect.TypeOf((*MockConfigurationContract)(nil).ShouldUpdateNodeGeolocationResource))
+}
+
 // ShouldUpdatePublciIPAndGeolocationDetails mocks base method.
 func (m *MockConfigurationContract) ShouldUpdatePublciIPAndGeolocationDetails() bool {
 	m.ctrl.T.Helper()
@@ -151,3 +613,17 @@ func (mr *MockConfigurationContractMockRecorder) ShouldUpdatePublciIPAndGeolocat
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldUpdatePublciIPAndGeolocationDetails", reflect.TypeOf((*MockConfigurationContract)(nil).ShouldUpdatePublciIPAndGeolocationDetails))
 }
+
+// ShouldUpdateTopologyLabels mocks base method.
+func (m *MockConfigurationContract) ShouldUpdateTopologyLabels() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldUpdateTopologyLabels")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ShouldUpdateTopologyLabels indicates an expected call of ShouldUpdateTopologyLabels.
+func (mr *MockConfigurationContractMockRecorder) ShouldUpdateTopologyLabels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldUpdateTopologyLabels", reflect.TypeOf((*MockConfigurationContract)(nil).ShouldUpdateTopologyLabels))
+}
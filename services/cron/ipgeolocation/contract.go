@@ -0,0 +1,105 @@
+package ipgeolocation
+
+import (
+	"context"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	cronContract "github.com/decentralized-cloud/edge-core/services/cron"
+)
+
+const (
+	// RunSucceeded determines that the run or the stage finished successfully
+	RunSucceeded = "succeeded"
+	// RunFailed determines that the run or the stage failed
+	RunFailed = "failed"
+	// RunSkipped determines that the run or the stage did not need to do anything, e.g. because the providers are
+	// throttled or the geolocation is managed by hand
+	RunSkipped = "skipped"
+)
+
+// StageResult contains the outcome of a single stage of a geolocation update run
+type StageResult struct {
+	// Stage is the name of the stage, e.g. node_get, provider or patch
+	Stage string `json:"stage"`
+
+	// Outcome is one of RunSucceeded, RunFailed or RunSkipped
+	Outcome string `json:"outcome"`
+
+	// Message explains the outcome, e.g. the error of a failed stage
+	Message string `json:"message,omitempty"`
+}
+
+// RunResult contains the outcome of a geolocation update run
+type RunResult struct {
+	// Trigger is what started the run, one of initial, cron, networkChange or refresh
+	Trigger string `json:"trigger"`
+
+	// StartTime is the time the run started
+	StartTime time.Time `json:"startTime"`
+
+	// FinishTime is the time the run finished
+	FinishTime time.Time `json:"finishTime"`
+
+	// Outcome is one of RunSucceeded, RunFailed or RunSkipped
+	Outcome string `json:"outcome"`
+
+	// Stages are the outcomes of the stages the run went through, in order
+	Stages []StageResult `json:"stages"`
+
+	// Provider is the name of the provider(s) the new details are resolved by
+	Provider string `json:"provider,omitempty"`
+
+	// Previous is the primary public IP address and geolocation details in the node labels before the run
+	Previous *labels.Geolocation `json:"previous,omitempty"`
+
+	// Current is the primary public IP address and geolocation details resolved by the run
+	Current *labels.Geolocation `json:"current,omitempty"`
+}
+
+// Status contains the node public IP address and geolocation details the updater last applied and the results
+// of its latest runs
+type Status struct {
+	// Geolocation is the primary public IP address and geolocation details of the last successful run, or the
+	// saved ones until the first run after a restart succeeds
+	Geolocation *labels.Geolocation `json:"geolocation,omitempty"`
+
+	// IPv4 is the IPv4 public IP address and geolocation details of the last successful run
+	IPv4 *labels.Geolocation `json:"ipv4,omitempty"`
+
+	// IPv6 is the IPv6 public IP address and geolocation details of the last successful run
+	IPv6 *labels.Geolocation `json:"ipv6,omitempty"`
+
+	// Latitude is the latitude of the primary location, or nil if the location is not known
+	Latitude *float64 `json:"latitude,omitempty"`
+
+	// Longitude is the longitude of the primary location, or nil if the location is not known
+	Longitude *float64 `json:"longitude,omitempty"`
+
+	// LastAttemptTime is the time the last run started
+	LastAttemptTime *time.Time `json:"lastAttemptTime,omitempty"`
+
+	// LastSuccessTime is the time the last successful run finished
+	LastSuccessTime *time.Time `json:"lastSuccessTime,omitempty"`
+
+	// Runs are the results of the latest runs, the most recent first
+	Runs []RunResult `json:"runs"`
+}
+
+// GeolocationUpdaterContract declares the methods to be implemented by the geolocation updater service
+type GeolocationUpdaterContract interface {
+	cronContract.CronContract
+
+	// Refresh updates the public IP address and geolocation details of the node right away. As a run in progress
+	// may have resolved the details before the refresh was requested, a single run is queued to follow it that all
+	// the refreshes requested in the meantime wait for. A refresh requested within the refresh minimum interval
+	// after the latest run started returns the result of that run instead.
+	// ctx: Mandatory. The reference to the context that bounds how long the caller waits for the run
+	// Returns the result of the run or error if the service is not started or the context is done first
+	Refresh(ctx context.Context) (*RunResult, error)
+
+	// GetStatus returns the public IP address and geolocation details the updater last applied and the results of
+	// its latest runs, without calling the providers or the Kubernetes API server
+	// Returns the status of the updater
+	GetStatus() Status
+}
@@ -0,0 +1,117 @@
+package ipgeolocation
+
+import (
+	"fmt"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// publicIPChangedReason is the reason of the event recorded when the node public IP address changes
+	publicIPChangedReason = "PublicIPChanged"
+
+	// locationChangedReason is the reason of the event recorded when the node location changes
+	locationChangedReason = "LocationChanged"
+
+	// geolocationFailedReason is the reason of the event recorded when resolving the node public IP address and
+	// geolocation details fails repeatedly
+	geolocationFailedReason = "GeolocationFailed"
+
+	// nodePatchFailedReason is the reason of the event recorded when patching the node labels fails repeatedly
+	nodePatchFailedReason = "GeolocationNodePatchFailed"
+
+	// eventSourceComponent is the component recorded as the source of the events
+	eventSourceComponent = "edge-core"
+)
+
+// recordChangeEvents records an event against the node for every change between the primary public IP address
+// and location in the previous labels of the node and the new ones
+func (service *cronService) recordChangeEvents(node *v1.Node, previous *labels.Geolocation, current *labels.Geolocation) {
+	// Nothing is recorded the first time the labels are written, as there is nothing to compare with
+	if previous == nil {
+		return
+	}
+
+	if previous.Ip != current.Ip {
+		publicIPChangesCounter.Inc()
+
+		service.eventRecorder.Eventf(
+			node,
+			v1.EventTypeNormal,
+			publicIPChangedReason,
+			"Public IP address changed from %s to %s",
+			previous.Ip,
+			current.Ip)
+	}
+
+	if previous.Loc != current.Loc {
+		service.eventRecorder.Eventf(
+			node,
+			v1.EventTypeNormal,
+			locationChangedReason,
+			"Location changed from %s to %s",
+			describeLocation(previous),
+			describeLocation(current))
+	}
+}
+
+// recordFailure increases the given consecutive failure counter and records a Warning event against the node
+// once the counter reaches the failure event threshold
+func (service *cronService) recordFailure(node *v1.Node, counter *int, reason string, err error) {
+	service.failuresLock.Lock()
+	*counter++
+	failures := *counter
+	service.failuresLock.Unlock()
+
+	if node == nil || failures < service.failureEventThreshold {
+		return
+	}
+
+	service.eventRecorder.Eventf(
+		node,
+		v1.EventTypeWarning,
+		reason,
+		"Failed %d times in a row: %v",
+		failures,
+		err)
+}
+
+// resetFailures resets the given consecutive failure counter after a success
+func (service *cronService) resetFailures(counter *int) {
+	service.failuresLock.Lock()
+	defer service.failuresLock.Unlock()
+
+	*counter = 0
+}
+
+func describeLocation(geolocation *labels.Geolocation) string {
+	if geolocation.Loc == "" {
+		return "<none>"
+	}
+
+	place := joinNonEmpty(geolocation.City, geolocation.Region, geolocation.Country)
+	if place == "" {
+		return geolocation.Loc
+	}
+
+	return fmt.Sprintf("%s (%s)", geolocation.Loc, place)
+}
+
+func joinNonEmpty(values ...string) string {
+	joined := ""
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+
+		if joined != "" {
+			joined += ", "
+		}
+
+		joined += value
+	}
+
+	return joined
+}
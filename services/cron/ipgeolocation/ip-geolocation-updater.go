@@ -4,48 +4,93 @@ package ipgeolocation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
 	"github.com/decentralized-cloud/edge-core/services/configuration"
-	cronContract "github.com/decentralized-cloud/edge-core/services/cron"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/state"
 	commonErrors "github.com/micro-business/go-core/system/errors"
 	cron "github.com/robfig/cron/v3"
-	"github.com/shengdoushi/base58"
 	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedCoreV1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 )
 
 type cronService struct {
-	logger            *zap.Logger
-	cronSpec          string
-	ipinfoUrl         string
-	ipinfoAccessToken string
-	cron              *cron.Cron
-	clientset         *kubernetes.Clientset
-	runningNodeName   string
-	clusterType       configuration.ClusterType
-}
-
-type ipinfoResponse struct {
-	Ip       string
-	Hostname string
-	City     string
-	Region   string
-	Country  string
-	Loc      string
-	Org      string
-	Postal   string
-	Timezone string
+	logger              *zap.Logger
+	cronSpec            string
+	geolocationProvider geolocation.GeolocationProviderContract
+	stateStore          state.StateStoreContract
+	addressFamilies     []configuration.AddressFamily
+	cron                *cron.Cron
+	clientset           *kubernetes.Clientset
+	dynamicClient       dynamic.Interface
+	runningNodeName     string
+	clusterType         configuration.ClusterType
+	heartbeatInterval   time.Duration
+
+	schedulePolicy  configuration.SchedulePolicy
+	scheduleOffset  time.Duration
+	randomLock      sync.Mutex
+	random          *rand.Rand
+	initialRunTimer *time.Timer
+
+	refreshOnNetworkChange bool
+	networkChangeDebounce  time.Duration
+	wanInterfaces          []string
+	refreshLock            sync.Mutex
+	refreshTimer           *time.Timer
+	lastNetworkChange      time.Time
+	refreshStopped         bool
+	stopNetworkWatcher     func()
+
+	updateTopologyLabels   bool
+	topologyRegionTemplate string
+	topologyZoneTemplate   string
+	geohashPrecisions      []int
+
+	asnNetworkTypes      map[string]string
+	movementThreshold    float64
+	holdLabelsOnMovement bool
+
+	stateFreshnessWindow time.Duration
+
+	updateNodeGeolocationResource bool
+	nodeGeolocationHistorySize    int
+
+	eventBroadcaster      record.EventBroadcaster
+	eventRecorder         record.EventRecorder
+	failureEventThreshold int
+	failuresLock          sync.Mutex
+	resolutionFailures    int
+	patchFailures         int
+	runFailures           int
+
+	runLock            sync.Mutex
+	currentRun         *runCall
+	nextRun            *runCall
+	lastRun            *runCall
+	refreshMinInterval time.Duration
+
+	statusLock     sync.Mutex
+	status         Status
+	runHistorySize int
 }
 
 var Live bool
@@ -56,15 +101,17 @@ func init() {
 	Ready = false
 }
 
-var acceptedCharactersForLabels = base58.NewAlphabet("ABCDEFGHJKLMNPQRSTUVWXYZ123456789abcdefghijkmnopqrstuvwxyz")
-
 // NewCronService creates new instance of the cronService, setting up all dependencies and returns the instance
 // logger: Mandatory. Reference to the logger service
 // configurationService: Mandatory. Reference to the service that provides required configurations
+// geolocationProvider: Mandatory. Reference to the provider that resolves the node public IP address and geolocation details
+// stateStore: Mandatory. Reference to the store that keeps the last known details across restarts
 // Returns the new service or error if something goes wrong
 func NewCronService(
 	logger *zap.Logger,
-	configurationService configuration.ConfigurationContract) (cronContract.CronContract, error) {
+	configurationService configuration.ConfigurationContract,
+	geolocationProvider geolocation.GeolocationProviderContract,
+	stateStore state.StateStoreContract) (GeolocationUpdaterContract, error) {
 	if logger == nil {
 		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
 	}
@@ -73,6 +120,14 @@ func NewCronService(
 		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
 	}
 
+	if geolocationProvider == nil {
+		return nil, commonErrors.NewArgumentNilError("geolocationProvider", "geolocationProvider is required")
+	}
+
+	if stateStore == nil {
+		return nil, commonErrors.NewArgumentNilError("stateStore", "stateStore is required")
+	}
+
 	clusterType, err := configurationService.GetEdgeClusterType()
 	if err != nil {
 		return nil, err
@@ -87,16 +142,20 @@ func NewCronService(
 		return nil, err
 	}
 
-	ipinfoUrl, err := configurationService.GetIpinfoUrl()
+	schedulePolicy, err := configurationService.GetGeolocationUpdaterSchedulePolicy()
 	if err != nil {
 		return nil, err
 	}
 
-	ipinfoAccessToken, err := configurationService.GetIpinfoAccessToken()
+	addressFamilies, err := configurationService.GetGeolocationAddressFamilies()
 	if err != nil {
 		return nil, err
 	}
 
+	if len(addressFamilies) == 0 {
+		return nil, commonErrors.NewArgumentError("addressFamilies", "at least one address family is required")
+	}
+
 	runningNodeName, err := configurationService.GetRunningNodeName()
 	if err != nil {
 		return nil, err
@@ -112,15 +171,113 @@ func NewCronService(
 		return nil, commonErrors.NewUnknownErrorWithError("Failed to create client set", err)
 	}
 
+	var dynamicClient dynamic.Interface
+	if dynamicClient, err = dynamic.NewForConfig(k8sRestConfig); err != nil {
+		return nil, commonErrors.NewUnknownErrorWithError("Failed to create dynamic client", err)
+	}
+
+	nodeGeolocationHistorySize, err := configurationService.GetNodeGeolocationHistorySize()
+	if err != nil {
+		return nil, err
+	}
+
+	failureEventThreshold, err := configurationService.GetGeolocationFailureEventThreshold()
+	if err != nil {
+		return nil, err
+	}
+
+	heartbeatInterval, err := configurationService.GetGeolocationHeartbeatInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	stateFreshnessWindow, err := configurationService.GetGeolocationStateFreshnessWindow()
+	if err != nil {
+		return nil, err
+	}
+
+	topologyRegionTemplate, err := configurationService.GetTopologyRegionTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	topologyZoneTemplate, err := configurationService.GetTopologyZoneTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	geohashPrecisions, err := configurationService.GetGeohashPrecisions()
+	if err != nil {
+		return nil, err
+	}
+
+	runHistorySize, err := configurationService.GetGeolocationRunHistorySize()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshMinInterval, err := configurationService.GetGeolocationRefreshMinInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	networkChangeDebounce, err := configurationService.GetNetworkChangeDebounce()
+	if err != nil {
+		return nil, err
+	}
+
+	asnNetworkTypes, err := configurationService.GetAsnNetworkTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	movementThreshold, err := configurationService.GetMovementThreshold()
+	if err != nil {
+		return nil, err
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+
 	return &cronService{
-		logger:            logger,
-		cronSpec:          cronSpec,
-		ipinfoUrl:         ipinfoUrl,
-		ipinfoAccessToken: ipinfoAccessToken,
-		cron:              cron.New(),
-		clientset:         clientset,
-		runningNodeName:   runningNodeName,
-		clusterType:       clusterType,
+		logger:                 logger,
+		cronSpec:               cronSpec,
+		geolocationProvider:    geolocationProvider,
+		stateStore:             stateStore,
+		addressFamilies:        addressFamilies,
+		cron:                   cron.New(),
+		clientset:              clientset,
+		dynamicClient:          dynamicClient,
+		runningNodeName:        runningNodeName,
+		schedulePolicy:         schedulePolicy,
+		scheduleOffset:         getScheduleOffset(runningNodeName, schedulePolicy.Spread),
+		random:                 rand.New(rand.NewSource(time.Now().UnixNano())),
+		refreshOnNetworkChange: configurationService.ShouldRefreshOnNetworkChange(),
+		networkChangeDebounce:  networkChangeDebounce,
+		wanInterfaces:          configurationService.GetWanInterfaces(),
+		clusterType:            clusterType,
+		heartbeatInterval:      heartbeatInterval,
+
+		stateFreshnessWindow: stateFreshnessWindow,
+
+		updateTopologyLabels:   configurationService.ShouldUpdateTopologyLabels(),
+		topologyRegionTemplate: topologyRegionTemplate,
+		topologyZoneTemplate:   topologyZoneTemplate,
+		geohashPrecisions:      geohashPrecisions,
+		asnNetworkTypes:        asnNetworkTypes,
+		movementThreshold:      movementThreshold,
+		holdLabelsOnMovement:   configurationService.ShouldHoldLabelsOnMovement(),
+
+		updateNodeGeolocationResource: configurationService.ShouldUpdateNodeGeolocationResource(),
+		nodeGeolocationHistorySize:    nodeGeolocationHistorySize,
+
+		eventBroadcaster: eventBroadcaster,
+		eventRecorder: eventBroadcaster.NewRecorder(
+			scheme.Scheme,
+			v1.EventSource{Component: eventSourceComponent, Host: runningNodeName}),
+		failureEventThreshold: failureEventThreshold,
+		status:                Status{Runs: []RunResult{}},
+		runHistorySize:        runHistorySize,
+		refreshMinInterval:    refreshMinInterval,
 	}, nil
 }
 
@@ -129,14 +286,51 @@ func NewCronService(
 func (service *cronService) Start() error {
 	service.logger.Info("Geolocation Updater service started")
 
-	_, err := service.cron.AddFunc(service.cronSpec, service.updateGeolocation)
+	// Loading the saved state up front surfaces a broken state file in the logs right away, and the status reports
+	// the last known details until the first run finishes
+	if savedState, err := service.stateStore.Load(); err == nil && savedState != nil && savedState.Geolocation != nil {
+		service.logger.Info("Loaded the saved geolocation state", zap.Time("updatedTime", savedState.UpdatedTime))
+		service.recordCurrentGeolocation(getSavedGeolocationDetails(savedState))
+	}
+
+	service.eventBroadcaster.StartRecordingToSink(&typedCoreV1.EventSinkImpl{Interface: service.clientset.CoreV1().Events("")})
+
+	schedule, err := cron.ParseStandard(service.cronSpec)
 	if err != nil {
 		return err
 	}
 
+	service.cron.Schedule(
+		&spreadSchedule{
+			schedule:  schedule,
+			offset:    service.scheduleOffset,
+			maxJitter: service.schedulePolicy.Jitter,
+			jitter:    service.getJitter,
+		},
+		cron.FuncJob(func() { service.run(cronTrigger, time.Time{}) }))
 	service.cron.Start()
 
-	go service.updateGeolocation()
+	initialRunDelay := service.scheduleOffset + service.getJitter()
+	service.logger.Info("Scheduled the first geolocation update", zap.Duration("delay", initialRunDelay))
+	service.initialRunTimer = time.AfterFunc(initialRunDelay, func() { service.run(initialTrigger, time.Time{}) })
+
+	// The cron schedule keeps running as the safety net for changes that are not visible on the node itself
+	if service.refreshOnNetworkChange {
+		stopNetworkWatcher, err := service.watchNetworkChanges()
+		if err != nil {
+			service.logger.Warn("Failed to watch network changes, relying on the cron schedule only", zap.Error(err))
+		} else {
+			service.refreshLock.Lock()
+			service.stopNetworkWatcher = stopNetworkWatcher
+
+			// The service may have been stopped while the watcher started
+			if service.refreshStopped {
+				stopNetworkWatcher()
+			}
+
+			service.refreshLock.Unlock()
+		}
+	}
 
 	Live = true
 	Ready = true
@@ -150,41 +344,194 @@ func (service *cronService) Stop() error {
 	Live = false
 	Ready = false
 
+	if service.initialRunTimer != nil {
+		service.initialRunTimer.Stop()
+	}
+
+	service.stopRefresh()
+
 	service.cron.Stop()
+	service.eventBroadcaster.Shutdown()
 
 	return nil
 }
 
 // updateGeolocation update the public IP address and the geolocation of the node
-func (service *cronService) updateGeolocation() {
+// trigger: Mandatory. What started the run
+// Returns the result of the run
+func (service *cronService) updateGeolocation(trigger string) *RunResult {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Minute)
 
 	defer cancelFunc()
 
-	shouldUpdate, err := service.shouldUpdateGeolocation(ctx)
+	result := newRunResult(trigger)
+
+	// Runs skipped because of the manual label or throttled providers count neither as a success nor a failure
+	runsCounter.Inc()
+
+	node, err := service.getNode(ctx)
+	result.addStageError(nodeGetStage, err)
+
 	if err != nil {
-		return
+		return result.finish(RunFailed)
 	}
 
-	if !shouldUpdate {
+	// Labels that can not be decoded, e.g. because they were edited by hand, are treated as not set and replaced,
+	// which does not fail the run
+	if previousNodeLabels, err := labels.Decode(node.Labels); err == nil {
+		result.Previous = previousNodeLabels.Geolocation
+	} else {
+		result.addStage(decodeStage, RunSkipped, "the previous labels are treated as not set: "+err.Error())
+		decodeFailuresCounter.Inc()
+	}
+
+	if !service.shouldUpdateGeolocation(node) {
 		service.logger.Debug("Manual update is set. Skipping geolocation update.")
 
-		return
+		return result.finish(RunSkipped)
 	}
 
-	service.logger.Info("Updating geolocation...")
+	service.logger.Info("Updating geolocation...", zap.String("trigger", trigger))
 
-	ipinfoResponse, err := service.getGeolocationDetails(ctx)
-	if err != nil {
-		return
+	// Runs requested because something changed always call the providers instead of using the saved details
+	var primaryGeolocationDetails *geolocation.GeolocationDetails
+	var geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails
+
+	if trigger == initialTrigger || trigger == cronTrigger {
+		primaryGeolocationDetails, geolocationDetailsByFamily = service.loadFreshState()
+	}
+
+	if primaryGeolocationDetails != nil {
+		result.addStage(providerStage, RunSkipped, "using the saved details as they are still fresh")
+	} else {
+		primaryGeolocationDetails, geolocationDetailsByFamily, err = service.resolveGeolocation(ctx, node)
+
+		var throttledErr *geolocation.ThrottledError
+		if errors.As(err, &throttledErr) {
+			result.addStage(providerStage, RunSkipped, err.Error())
+		} else {
+			result.addStageError(providerStage, err)
+		}
+
+		if primaryGeolocationDetails != nil {
+			service.saveState(primaryGeolocationDetails, geolocationDetailsByFamily)
+		}
+	}
+
+	// The public IP address keeps being resolved while the location is overridden, only the location is replaced
+	primaryGeolocationDetails, geolocationDetailsByFamily = service.applyOverride(node, primaryGeolocationDetails, geolocationDetailsByFamily)
+	if primaryGeolocationDetails == nil {
+		return result.finish(RunSkipped)
 	}
 
-	err = service.updateNode(ctx, ipinfoResponse)
+	result.Provider = primaryGeolocationDetails.Provider
+	result.Current = service.toNetworkClassifiedGeolocation(primaryGeolocationDetails)
+
+	if service.holdMovement(ctx, node, primaryGeolocationDetails) {
+		result.addStage(movementStage, RunSkipped, "the movement of the node is not acknowledged")
+
+		return result.finish(RunSkipped)
+	}
+
+	err = service.updateNode(ctx, node, primaryGeolocationDetails, geolocationDetailsByFamily)
+	result.addStageError(patchStage, err)
+
 	if err != nil {
-		return
+		return result.finish(RunFailed)
+	}
+
+	// The labels are what the workloads schedule on, so the details are applied once they are patched, even if
+	// the NodeGeolocation custom resource can not be updated
+	service.setLocationInfoMetric(primaryGeolocationDetails)
+	service.recordCurrentGeolocation(primaryGeolocationDetails, geolocationDetailsByFamily)
+
+	if service.updateNodeGeolocationResource {
+		err = service.updateNodeGeolocation(ctx, node, primaryGeolocationDetails, geolocationDetailsByFamily)
+		result.addStageError(nodeGeolocationStage, err)
+
+		if err != nil {
+			return result.finish(RunFailed)
+		}
 	}
 
 	service.logger.Info("Finished updating geolocation details.")
+
+	return result.finish(RunSucceeded)
+}
+
+// resolveGeolocation probes the public IP address and geolocation details of every configured address family
+// Returns the primary details and the details per address family, or nil and the error of the last address family
+// if no address family succeeded
+func (service *cronService) resolveGeolocation(
+	ctx context.Context,
+	node *v1.Node) (*geolocation.GeolocationDetails, map[configuration.AddressFamily]*geolocation.GeolocationDetails, error) {
+	// Each address family is probed separately, the first one that succeeds provides the primary public IP address
+	var primaryGeolocationDetails *geolocation.GeolocationDetails
+	geolocationDetailsByFamily := map[configuration.AddressFamily]*geolocation.GeolocationDetails{}
+	var lastErr error
+
+	for index, addressFamily := range service.addressFamilies {
+		start := time.Now()
+		geolocationDetails, err := service.getGeolocationDetails(ctx, addressFamily, len(service.addressFamilies)-index)
+		providerCallDuration.WithLabelValues(addressFamily.String(), getResult(err)).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			// Expected on nodes that do not have a public address of this family, e.g. IPv6 only nodes
+			service.logger.Info(
+				"Could not resolve public IP address and geolocation details",
+				zap.String("addressFamily", addressFamily.String()),
+				zap.Error(err))
+
+			lastErr = err
+
+			continue
+		}
+
+		if primaryGeolocationDetails == nil {
+			primaryGeolocationDetails = geolocationDetails
+		}
+
+		geolocationDetailsByFamily[addressFamily] = geolocationDetails
+	}
+
+	if primaryGeolocationDetails == nil {
+		// Providers that are waiting for their rate limit or quota to reset are not failing, the run is only
+		// postponed until they can be called again
+		var throttledErr *geolocation.ThrottledError
+		if errors.As(lastErr, &throttledErr) {
+			service.logger.Info(
+				"Geolocation providers are throttled. Skipping geolocation update.",
+				zap.String("provider", throttledErr.Provider),
+				zap.Time("until", throttledErr.Until))
+
+			return nil, nil, lastErr
+		}
+
+		service.logger.Error("Failed to resolve public IP address and geolocation details for any address family")
+		service.recordFailure(node, &service.resolutionFailures, geolocationFailedReason, lastErr)
+
+		return nil, nil, lastErr
+	}
+
+	service.resetFailures(&service.resolutionFailures)
+
+	return primaryGeolocationDetails, geolocationDetailsByFamily, nil
+}
+
+// getGeolocationDetails resolves the details of the given address family within its share of the time left, so
+// an address family the providers do not respond for still leaves time for the ones after it
+func (service *cronService) getGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily,
+	remainingAddressFamilies int) (*geolocation.GeolocationDetails, error) {
+	if deadline, ok := ctx.Deadline(); ok && remainingAddressFamilies > 1 {
+		var cancelFunc context.CancelFunc
+
+		ctx, cancelFunc = context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remainingAddressFamilies))
+		defer cancelFunc()
+	}
+
+	return service.geolocationProvider.GetGeolocationDetails(ctx, addressFamily)
 }
 
 func getRestConfig(logger *zap.Logger) (*rest.Config, error) {
@@ -211,100 +558,145 @@ func getRestConfig(logger *zap.Logger) (*rest.Config, error) {
 	return rest.InClusterConfig()
 }
 
-func (service *cronService) shouldUpdateGeolocation(ctx context.Context) (bool, error) {
+func (service *cronService) getNode(ctx context.Context) (*v1.Node, error) {
+	start := time.Now()
 	node, err := service.clientset.CoreV1().Nodes().Get(ctx, service.runningNodeName, metav1.GetOptions{})
+	observeKubernetesCall("get_node", start, err)
+
 	if err != nil {
 		service.logger.Error(
 			"Failed to retrieve node information",
 			zap.String("runningNodeName", service.runningNodeName),
 			zap.Error(err))
 
-		return false, err
+		return nil, err
 	}
 
-	if value, ok := node.Labels["edgecloud9.geolocation.manual"]; ok {
+	return node, nil
+}
+
+func (service *cronService) shouldUpdateGeolocation(node *v1.Node) bool {
+	if value, ok := node.Labels[labels.GeolocationManualLabel]; ok {
 		if value == "false" {
-			return false, nil
+			return false
 		}
 
-		return true, nil
+		return true
 	}
 
-	return true, nil
+	return true
 }
 
-func (service *cronService) getGeolocationDetails(ctx context.Context) (*ipinfoResponse, error) {
-	httpClient := &http.Client{}
-	request, err := http.NewRequestWithContext(ctx, "GET", service.ipinfoUrl, nil)
-	if err != nil {
-		service.logger.Error(
-			"Failed to create a new request to Ipinfo",
-			zap.String("ipinfoUrl", service.ipinfoUrl),
-			zap.Error(err))
+func (service *cronService) updateNode(
+	ctx context.Context,
+	node *v1.Node,
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) error {
+	nodeLabels := labels.NodeLabels{
+		Geolocation: service.toNetworkClassifiedGeolocation(primaryGeolocationDetails),
+	}
 
-		return nil, err
+	// Families that failed are left out of the patch so their labels keep the last known values
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv4]; ok {
+		nodeLabels.IPv4 = service.toNetworkClassifiedGeolocation(geolocationDetails)
 	}
 
-	if strings.Trim(service.ipinfoAccessToken, " ") != "" {
-		request.Header.Set("Authorization", "Bearer "+service.ipinfoAccessToken)
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv6]; ok {
+		nodeLabels.IPv6 = service.toNetworkClassifiedGeolocation(geolocationDetails)
 	}
 
-	response, err := httpClient.Do(request)
+	// Labels that can not be decoded, e.g. because they were edited by hand, are treated as not set
+	previousNodeLabels, err := labels.Decode(node.Labels)
 	if err != nil {
-		service.logger.Error("Failed to send request to Ipinfo", zap.String("ipinfoUrl", service.ipinfoUrl), zap.Error(err))
+		service.logger.Debug("Failed to decode the previous node labels", zap.Error(err))
 
-		return nil, err
+		previousNodeLabels = &labels.NodeLabels{}
 	}
 
-	defer response.Body.Close()
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		service.logger.Error(
-			"Failed to read Ipinfo reponse body",
-			zap.String("ipinfoUrl", service.ipinfoUrl),
-			zap.String("response", string(body)),
-			zap.Error(err))
+	if nodeLabels.Geolocation.Loc != "" {
+		if nodeLabels.Geohashes, err = labels.Geohashes(nodeLabels.Geolocation.Loc, service.geohashPrecisions); err != nil {
+			service.logger.Warn("Failed to compute the geohash labels", zap.String("loc", nodeLabels.Geolocation.Loc), zap.Error(err))
+		}
+	}
 
-		return nil, err
+	nodeLabels.Reachability = classifyReachability(node, primaryGeolocationDetails.Ip)
+	setReachabilityMetric(nodeLabels.Reachability)
+
+	desiredLabels := labels.Encode(nodeLabels)
+
+	topologyLabels, removedTopologyLabels, topologyManaged := service.getTopologyLabels(node, nodeLabels.Geolocation)
+	for key, value := range topologyLabels {
+		desiredLabels[key] = value
 	}
 
-	var ipinfoResponse ipinfoResponse
+	// Only the keys whose value changed are patched, so a run that finds nothing new does not touch the node
+	changedLabels := labels.Diff(node.Labels, desiredLabels)
+	// A nil value removes the annotation from the node
+	changedAnnotations := map[string]*string{}
 
-	err = json.Unmarshal(body, &ipinfoResponse)
-	if err != nil {
-		service.logger.Error(
-			"Can't deserialize Ipinfo response",
-			zap.String("ipinfoUrl", service.ipinfoUrl),
-			zap.String("response", string(body)),
-			zap.Error(err))
+	if node.Annotations[topologyManagedAnnotation] != topologyManaged {
+		if topologyManaged == "" {
+			changedAnnotations[topologyManagedAnnotation] = nil
+		} else {
+			changedAnnotations[topologyManagedAnnotation] = &topologyManaged
+		}
+	}
 
-		return nil, err
+	// The labels are only updated after a held movement is acknowledged or the node returned, so the movement is
+	// settled and the annotations are cleared
+	for _, key := range []string{pendingMovementAnnotation, movementAcknowledgedAnnotation} {
+		if _, ok := node.Annotations[key]; ok {
+			changedAnnotations[key] = nil
+		}
 	}
 
-	return &ipinfoResponse, nil
-}
+	currentTime := time.Now()
+	timestamps := labels.NodeLabels{}
+
+	if hasLabelWithPrefix(changedLabels, labels.PublicLabelPrefix) {
+		timestamps.PublicLastUpdatedTime = &currentTime
+	}
+
+	if hasLabelWithPrefix(changedLabels, labels.GeolocationLabelPrefix) {
+		timestamps.GeolocationLastUpdatedTime = &currentTime
+	}
+
+	if service.heartbeatInterval > 0 && (len(changedLabels) > 0 ||
+		previousNodeLabels.LastCheckedTime == nil ||
+		currentTime.Sub(*previousNodeLabels.LastCheckedTime) >= service.heartbeatInterval) {
+		timestamps.LastCheckedTime = &currentTime
+	}
+
+	for key, value := range labels.Encode(timestamps) {
+		changedLabels[key] = value
+	}
 
-func (service *cronService) updateNode(ctx context.Context, ipinfoResponse *ipinfoResponse) error {
-	currentTime := base58.Encode([]byte(time.Now().Format(time.RFC3339Nano)), acceptedCharactersForLabels)
+	if len(changedLabels) == 0 && len(removedTopologyLabels) == 0 && len(changedAnnotations) == 0 {
+		service.logger.Debug("Geolocation details did not change. Skipping node update.")
+
+		return nil
+	}
 
 	patch := struct {
 		Metadata struct {
-			Labels map[string]string `json:"labels"`
+			Labels      map[string]*string `json:"labels,omitempty"`
+			Annotations map[string]*string `json:"annotations,omitempty"`
 		} `json:"metadata"`
 	}{}
 
-	patch.Metadata.Labels = map[string]string{}
-	patch.Metadata.Labels["edgecloud9.public.lastUpdatedTime"] = currentTime
-	patch.Metadata.Labels["edgecloud9.public.ip"] = base58.Encode([]byte(ipinfoResponse.Ip), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.public.hostname"] = base58.Encode([]byte(ipinfoResponse.Hostname), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.lastUpdatedTime"] = currentTime
-	patch.Metadata.Labels["edgecloud9.geolocation.loc"] = base58.Encode([]byte(ipinfoResponse.Loc), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.city"] = base58.Encode([]byte(ipinfoResponse.City), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.region"] = base58.Encode([]byte(ipinfoResponse.Region), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.country"] = base58.Encode([]byte(ipinfoResponse.Country), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.org"] = base58.Encode([]byte(ipinfoResponse.Org), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.postal"] = base58.Encode([]byte(ipinfoResponse.Postal), acceptedCharactersForLabels)
-	patch.Metadata.Labels["edgecloud9.geolocation.timezone"] = base58.Encode([]byte(ipinfoResponse.Timezone), acceptedCharactersForLabels)
+	// Like the annotations, a nil value removes the label from the node
+	patch.Metadata.Labels = map[string]*string{}
+
+	for key, value := range changedLabels {
+		value := value
+		patch.Metadata.Labels[key] = &value
+	}
+
+	for _, key := range removedTopologyLabels {
+		patch.Metadata.Labels[key] = nil
+	}
+
+	patch.Metadata.Annotations = changedAnnotations
 
 	patchJson, err := json.Marshal(patch)
 	if err != nil {
@@ -316,14 +708,88 @@ func (service *cronService) updateNode(ctx context.Context, ipinfoResponse *ipin
 		return err
 	}
 
-	if _, err = service.clientset.CoreV1().Nodes().Patch(ctx, service.runningNodeName, types.MergePatchType, patchJson, metav1.PatchOptions{}); err != nil {
+	start := time.Now()
+	_, err = service.clientset.CoreV1().Nodes().Patch(ctx, service.runningNodeName, types.MergePatchType, patchJson, metav1.PatchOptions{})
+	observeKubernetesCall("patch_node", start, err)
+
+	if err != nil {
 		service.logger.Error(
 			"Failed to retrieve node information",
 			zap.String("runningNodeName", service.runningNodeName),
 			zap.Error(err))
+		service.recordFailure(node, &service.patchFailures, nodePatchFailedReason, err)
 
 		return err
 	}
 
+	service.resetFailures(&service.patchFailures)
+	service.recordChangeEvents(node, previousNodeLabels.Geolocation, nodeLabels.Geolocation)
+
 	return nil
 }
+
+func hasLabelWithPrefix(nodeLabels map[string]string, prefix string) bool {
+	for key := range nodeLabels {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toLabelsGeolocation(geolocationDetails *geolocation.GeolocationDetails) *labels.Geolocation {
+	return &labels.Geolocation{
+		Ip:       geolocationDetails.Ip,
+		Port:     geolocationDetails.PublicPort,
+		NatType:  geolocationDetails.NatType,
+		Hostname: geolocationDetails.Hostname,
+		Loc:      geolocationDetails.Loc,
+		City:     geolocationDetails.City,
+		Region:   geolocationDetails.Region,
+		Country:  geolocationDetails.Country,
+		Org:      geolocationDetails.Org,
+		Postal:   geolocationDetails.Postal,
+		Timezone: geolocationDetails.Timezone,
+		Provider: geolocationDetails.Provider,
+		Source:   geolocationDetails.Source,
+	}
+}
+
+// toNetworkClassifiedGeolocation converts the given details into labels and splits the organization into the
+// autonomous system number, organization name and network type
+func (service *cronService) toNetworkClassifiedGeolocation(geolocationDetails *geolocation.GeolocationDetails) *labels.Geolocation {
+	labelsGeolocation := toLabelsGeolocation(geolocationDetails)
+	labelsGeolocation.Asn, labelsGeolocation.OrgName = labels.ParseOrg(labelsGeolocation.Org)
+
+	if labelsGeolocation.Source == "" {
+		labelsGeolocation.Source = labels.ProviderSource
+	}
+
+	if labelsGeolocation.Asn != "" {
+		labelsGeolocation.NetworkType = labels.NetworkTypeUnknown
+		if networkType, ok := service.asnNetworkTypes[labelsGeolocation.Asn]; ok {
+			labelsGeolocation.NetworkType = networkType
+		}
+	}
+
+	return labelsGeolocation
+}
+
+func fromLabelsGeolocation(labelsGeolocation *labels.Geolocation) *geolocation.GeolocationDetails {
+	return &geolocation.GeolocationDetails{
+		Ip:         labelsGeolocation.Ip,
+		PublicPort: labelsGeolocation.Port,
+		NatType:    labelsGeolocation.NatType,
+		Hostname:   labelsGeolocation.Hostname,
+		Loc:        labelsGeolocation.Loc,
+		City:       labelsGeolocation.City,
+		Region:     labelsGeolocation.Region,
+		Country:    labelsGeolocation.Country,
+		Org:        labelsGeolocation.Org,
+		Postal:     labelsGeolocation.Postal,
+		Timezone:   labelsGeolocation.Timezone,
+		Provider:   labelsGeolocation.Provider,
+		Source:     labelsGeolocation.Source,
+	}
+}
@@ -0,0 +1,174 @@
+package ipgeolocation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// nodeGetStage is the stage of the run that retrieves the node the edge-core is running on
+	nodeGetStage = "node_get"
+	// providerStage is the stage of the run that resolves the public IP address and geolocation details
+	providerStage = "provider"
+	// decodeStage is the stage of the run that decodes the previous node labels
+	decodeStage = "decode"
+	// patchStage is the stage of the run that patches the node labels
+	patchStage = "patch"
+	// movementStage is the stage of the run that detects whether the node moved
+	movementStage = "movement"
+	// nodeGeolocationStage is the stage of the run that updates the NodeGeolocation custom resource
+	nodeGeolocationStage = "node_geolocation"
+)
+
+var (
+	runsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "edge_core_geolocation_updater_runs_total",
+			Help: "Number of geolocation update runs that started",
+		})
+
+	runSuccessesCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "edge_core_geolocation_updater_run_successes_total",
+			Help: "Number of geolocation update runs that finished successfully",
+		})
+
+	runFailuresCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "edge_core_geolocation_updater_run_failures_total",
+			Help: "Number of failures of the geolocation update runs by the stage that failed",
+		},
+		[]string{"stage"})
+
+	// decodeFailuresCounter is kept apart from the run failures, as a run that can not decode the previous labels
+	// replaces them and can still succeed
+	decodeFailuresCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "edge_core_geolocation_updater_label_decode_failures_total",
+			Help: "Number of geolocation update runs that could not decode the previous node labels",
+		})
+
+	providerCallDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "edge_core_geolocation_provider_call_duration_seconds",
+			Help:    "Duration of resolving the public IP address and geolocation details of an address family",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+		},
+		[]string{"address_family", "result"})
+
+	kubernetesCallDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "edge_core_geolocation_kubernetes_api_call_duration_seconds",
+			Help:    "Duration of the Kubernetes API calls made by the geolocation updater",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation", "result"})
+
+	lastSuccessTimestampGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "edge_core_geolocation_updater_last_success_timestamp_seconds",
+			Help: "Unix time of the last geolocation update run that finished successfully",
+		})
+
+	consecutiveFailuresGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "edge_core_geolocation_updater_consecutive_failures",
+			Help: "Number of geolocation update runs in a row that failed",
+		})
+
+	// locationInfoGauge carries the location in plain text labels, as the node labels are encoded and can not be
+	// used by dashboards
+	locationInfoGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "edge_core_node_location_info",
+			Help: "Location of the node resolved by the last geolocation update run that finished successfully, always 1",
+		},
+		[]string{"node", "city", "region", "country", "lat", "lon", "asn", "org", "provider"})
+
+	publicIPChangesCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "edge_core_public_ip_changes_total",
+			Help: "Number of times the public IP address of the node changed",
+		})
+)
+
+// recordRunOutcome counts the outcome of a finished run once, so a run that continued after a failed stage, e.g.
+// with an overridden location while the providers fail, is not counted as both a success and a failure. Skipped
+// runs count neither as a success nor a failure.
+func (service *cronService) recordRunOutcome(result *RunResult) {
+	switch result.Outcome {
+	case RunSucceeded:
+		service.recordRunSuccess()
+	case RunFailed:
+		for _, stage := range result.Stages {
+			if stage.Outcome == RunFailed {
+				service.recordRunFailure(stage.Stage)
+
+				return
+			}
+		}
+	}
+}
+
+// recordRunSuccess counts a run that finished successfully and resets the consecutive failures
+func (service *cronService) recordRunSuccess() {
+	service.failuresLock.Lock()
+	service.runFailures = 0
+	service.failuresLock.Unlock()
+
+	runSuccessesCounter.Inc()
+	lastSuccessTimestampGauge.SetToCurrentTime()
+	consecutiveFailuresGauge.Set(0)
+}
+
+// recordRunFailure counts a run that failed at the given stage
+func (service *cronService) recordRunFailure(stage string) {
+	service.failuresLock.Lock()
+	service.runFailures++
+	failures := service.runFailures
+	service.failuresLock.Unlock()
+
+	runFailuresCounter.WithLabelValues(stage).Inc()
+	consecutiveFailuresGauge.Set(float64(failures))
+}
+
+// setLocationInfoMetric replaces the location info series with the one of the given geolocation details
+func (service *cronService) setLocationInfoMetric(geolocationDetails *geolocation.GeolocationDetails) {
+	latitude, longitude := "", ""
+	if coordinates := strings.Split(geolocationDetails.Loc, ","); len(coordinates) == 2 {
+		latitude, longitude = strings.TrimSpace(coordinates[0]), strings.TrimSpace(coordinates[1])
+	}
+
+	asn, org := labels.ParseOrg(geolocationDetails.Org)
+
+	// Only a single series is kept, so the old location does not linger on the dashboards after the node moves
+	locationInfoGauge.Reset()
+	locationInfoGauge.WithLabelValues(
+		service.runningNodeName,
+		geolocationDetails.City,
+		geolocationDetails.Region,
+		geolocationDetails.Country,
+		latitude,
+		longitude,
+		asn,
+		org,
+		geolocationDetails.Provider).Set(1)
+}
+
+// observeKubernetesCall records the duration of a Kubernetes API call that started at the given time
+func observeKubernetesCall(operation string, start time.Time, err error) {
+	kubernetesCallDuration.WithLabelValues(operation, getResult(err)).Observe(time.Since(start).Seconds())
+}
+
+func getResult(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
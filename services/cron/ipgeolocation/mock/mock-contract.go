@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/cron/ipgeolocation/contract.go
+
+// Package mock_ipgeolocation is a generated GoMock package.
+package mock_ipgeolocation
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ipgeolocation "github.com/decentralized-cloud/edge-core/services/cron/ipgeolocation"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGeolocationUpdaterContract is a mock of GeolocationUpdaterContract interface.
+type MockGeolocationUpdaterContract struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeolocationUpdaterContractMockRecorder
+}
+
+// MockGeolocationUpdaterContractMockRecorder is the mock recorder for MockGeolocationUpdaterContract.
+type MockGeolocationUpdaterContractMockRecorder struct {
+	mock *MockGeolocationUpdaterContract
+}
+
+// NewMockGeolocationUpdaterContract creates a new mock instance.
+func NewMockGeolocationUpdaterContract(ctrl *gomock.Controller) *MockGeolocationUpdaterContract {
+	mock := &MockGeolocationUpdaterContract{ctrl: ctrl}
+	mock.recorder = &MockGeolocationUpdaterContractMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeolocationUpdaterContract) EXPECT() *MockGeolocationUpdaterContractMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockGeolocationUpdaterContract) GetStatus() ipgeolocation.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus")
+	ret0, _ := ret[0].(ipgeolocation.Status)
+	return ret0
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockGeolocationUpdaterContractMockRecorder) GetStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockGeolocationUpdaterContract)(nil).GetStatus))
+}
+
+// Refresh mocks base method.
+func (m *MockGeolocationUpdaterContract) Refresh(ctx context.Context) (*ipgeolocation.RunResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(*ipgeolocation.RunResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockGeolocationUpdaterContractMockRecorder) Refresh(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockGeolocationUpdaterContract)(nil).Refresh), ctx)
+}
+
+// Start mocks base method.
+func (m *MockGeolocationUpdaterContract) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockGeolocationUpdaterContractMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockGeolocationUpdaterContract)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockGeolocationUpdaterContract) Stop() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockGeolocationUpdaterContractMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockGeolocationUpdaterContract)(nil).Stop))
+}
@@ -0,0 +1,183 @@
+package ipgeolocation
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// nodeMovedReason is the reason of the event recorded when the node location moves further than the movement
+	// threshold
+	nodeMovedReason = "NodeMoved"
+
+	// pendingMovementAnnotation contains the new location of a node whose labels are held because it moved
+	pendingMovementAnnotation = "edgecloud9.geolocation.pendingMovement"
+
+	// movementAcknowledgedAnnotation is the annotation operators set to "true" to accept a held movement
+	movementAcknowledgedAnnotation = "edgecloud9.geolocation.movementAcknowledged"
+
+	// earthRadius is the mean radius of the earth in kilometers
+	earthRadius = 6371.0
+)
+
+var (
+	movementsCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "edge_core_node_movements_total",
+			Help: "Number of times the node location moved further than the movement threshold",
+		})
+
+	lastMovementDistanceGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "edge_core_node_last_movement_distance_kilometers",
+			Help: "Great-circle distance of the last time the node location moved further than the movement threshold",
+		})
+)
+
+// holdMovement detects whether the node moved further than the movement threshold since the labels were last
+// updated, records the movement and determines whether the labels must be held until the movement is acknowledged
+// Returns true if the labels must not be updated otherwise returns false
+func (service *cronService) holdMovement(ctx context.Context, node *v1.Node, geolocationDetails *geolocation.GeolocationDetails) bool {
+	if service.movementThreshold <= 0 {
+		return false
+	}
+
+	// Locations set by hand through the override annotations are moved on purpose
+	if geolocationDetails.Source == labels.ManualSource {
+		return false
+	}
+
+	previousNodeLabels, err := labels.Decode(node.Labels)
+	if err != nil || previousNodeLabels.Geolocation == nil {
+		return false
+	}
+
+	distance, ok := getDistance(previousNodeLabels.Geolocation.Loc, geolocationDetails.Loc)
+	if !ok || distance <= service.movementThreshold {
+		return false
+	}
+
+	// A held movement is detected again on every run, it is only recorded the first time
+	pendingMovement, isPending := node.Annotations[pendingMovementAnnotation]
+	if !isPending || pendingMovement != geolocationDetails.Loc {
+		service.logger.Info(
+			"Node moved",
+			zap.String("from", previousNodeLabels.Geolocation.Loc),
+			zap.String("to", geolocationDetails.Loc),
+			zap.Float64("distance", distance))
+
+		movementsCounter.Inc()
+		lastMovementDistanceGauge.Set(distance)
+		service.eventRecorder.Eventf(
+			node,
+			v1.EventTypeWarning,
+			nodeMovedReason,
+			"Location moved %.0f km from %s to %s",
+			distance,
+			describeLocation(previousNodeLabels.Geolocation),
+			describeLocation(toLabelsGeolocation(geolocationDetails)))
+	}
+
+	if !service.holdLabelsOnMovement || node.Annotations[movementAcknowledgedAnnotation] == "true" {
+		return false
+	}
+
+	if !isPending || pendingMovement != geolocationDetails.Loc {
+		service.setPendingMovement(ctx, geolocationDetails.Loc)
+	}
+
+	service.logger.Info(
+		"Node movement is not acknowledged. Skipping node update.",
+		zap.String("annotation", movementAcknowledgedAnnotation))
+
+	return true
+}
+
+// setPendingMovement records the new location of the node in the pending movement annotation, so operators can
+// review it before acknowledging the movement
+func (service *cronService) setPendingMovement(ctx context.Context, loc string) {
+	patch := struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+	}{}
+
+	patch.Metadata.Annotations = map[string]string{pendingMovementAnnotation: loc}
+
+	patchJson, err := json.Marshal(patch)
+	if err != nil {
+		service.logger.Error("Failed to serialize the pending movement", zap.Error(err))
+
+		return
+	}
+
+	start := time.Now()
+	_, err = service.clientset.CoreV1().Nodes().Patch(ctx, service.runningNodeName, types.MergePatchType, patchJson, metav1.PatchOptions{})
+	observeKubernetesCall("patch_node", start, err)
+
+	if err != nil {
+		service.logger.Error(
+			"Failed to set the pending movement annotation",
+			zap.String("runningNodeName", service.runningNodeName),
+			zap.Error(err))
+	}
+}
+
+// getDistance computes the great-circle distance in kilometers between two comma separated latitude and longitude
+// pairs using the haversine formula
+// Returns the distance, or false if either location can not be parsed
+func getDistance(from string, to string) (float64, bool) {
+	fromLatitude, fromLongitude, ok := parseLoc(from)
+	if !ok {
+		return 0, false
+	}
+
+	toLatitude, toLongitude, ok := parseLoc(to)
+	if !ok {
+		return 0, false
+	}
+
+	latitudeDelta := toRadians(toLatitude - fromLatitude)
+	longitudeDelta := toRadians(toLongitude - fromLongitude)
+
+	a := math.Sin(latitudeDelta/2)*math.Sin(latitudeDelta/2) +
+		math.Cos(toRadians(fromLatitude))*math.Cos(toRadians(toLatitude))*math.Sin(longitudeDelta/2)*math.Sin(longitudeDelta/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a))), true
+}
+
+func parseLoc(loc string) (float64, float64, bool) {
+	coordinates := strings.Split(loc, ",")
+	if len(coordinates) != 2 {
+		return 0, 0, false
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return latitude, longitude, true
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
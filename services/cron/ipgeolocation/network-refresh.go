@@ -0,0 +1,81 @@
+package ipgeolocation
+
+import (
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// containerInterfacePrefixes are the prefixes of the network interfaces created by the container runtime and the
+// CNI plugins, whose address changes never affect the public IP address of the node
+var containerInterfacePrefixes = []string{
+	"lo", "veth", "cni", "flannel", "cali", "cilium", "lxc", "docker", "kube-", "vxlan", "tunl", "weave", "virbr"}
+
+// requestRefresh schedules a geolocation update once the network has not changed for the debounce interval,
+// pushing back an update that is already scheduled. Nothing is scheduled once the refreshes are stopped.
+func (service *cronService) requestRefresh(reason string) {
+	service.refreshLock.Lock()
+	defer service.refreshLock.Unlock()
+
+	if service.refreshStopped {
+		return
+	}
+
+	service.logger.Debug("Network changed", zap.String("reason", reason))
+	service.lastNetworkChange = time.Now()
+
+	if service.refreshTimer != nil && service.refreshTimer.Stop() {
+		service.refreshTimer.Reset(service.networkChangeDebounce)
+
+		return
+	}
+
+	service.refreshTimer = time.AfterFunc(service.networkChangeDebounce, func() {
+		service.refreshLock.Lock()
+		changedTime := service.lastNetworkChange
+		service.refreshLock.Unlock()
+
+		service.logger.Info("Refreshing geolocation after network change")
+		service.run(networkChangeTrigger, changedTime)
+	})
+}
+
+// stopRefresh cancels the network watcher and the scheduled geolocation update, and stops new ones from being
+// scheduled
+func (service *cronService) stopRefresh() {
+	service.refreshLock.Lock()
+	defer service.refreshLock.Unlock()
+
+	service.refreshStopped = true
+
+	if service.stopNetworkWatcher != nil {
+		service.stopNetworkWatcher()
+	}
+
+	if service.refreshTimer != nil {
+		service.refreshTimer.Stop()
+	}
+}
+
+// isWanInterface determines whether address changes of the given network interface can change the public IP
+// address of the node
+func (service *cronService) isWanInterface(name string) bool {
+	if len(service.wanInterfaces) > 0 {
+		for _, wanInterface := range service.wanInterfaces {
+			if name == wanInterface {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, prefix := range containerInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
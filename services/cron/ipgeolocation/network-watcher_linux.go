@@ -0,0 +1,360 @@
+//go:build linux
+// +build linux
+
+package ipgeolocation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// minNetworkWatcherBackoff is the initial wait before the netlink socket is opened again after it failed
+	minNetworkWatcherBackoff = time.Second
+	// maxNetworkWatcherBackoff is the maximum wait before the netlink socket is opened again after it failed
+	maxNetworkWatcherBackoff = time.Minute
+)
+
+// networkSnapshot is the last seen addresses of the WAN interfaces and default routes of the node, so the
+// notifications that repeat them, e.g. the ones the IPv6 router advertisements renew the address lifetimes with,
+// do not request a refresh
+type networkSnapshot struct {
+	addresses     map[int]string
+	defaultRoutes string
+}
+
+// watchNetworkChanges subscribes to the netlink address and route notifications of the node and requests a
+// refresh whenever the default route or an address of a WAN interface changes, until the returned function is called
+// Returns the function that stops watching or error if the subscription fails
+func (service *cronService) watchNetworkChanges() (func(), error) {
+	fd, err := openNetlinkSocket()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+
+	go func() {
+		backoff := minNetworkWatcherBackoff
+
+		for {
+			started := time.Now()
+			err := service.readNetworkChanges(ctx, fd)
+			unix.Close(fd)
+
+			if err == nil {
+				return
+			}
+
+			// A socket that worked for a while failed for a new reason, so the backoff starts over
+			if time.Since(started) > maxNetworkWatcherBackoff {
+				backoff = minNetworkWatcherBackoff
+			}
+
+			service.logger.Error(
+				"Failed to read the netlink notifications, retrying",
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+
+			for {
+				if !waitForNetworkWatcher(ctx, backoff) {
+					return
+				}
+
+				backoff *= 2
+				if backoff > maxNetworkWatcherBackoff {
+					backoff = maxNetworkWatcherBackoff
+				}
+
+				if fd, err = openNetlinkSocket(); err == nil {
+					break
+				}
+
+				service.logger.Error(
+					"Failed to subscribe to the netlink notifications, retrying",
+					zap.Duration("backoff", backoff),
+					zap.Error(err))
+			}
+
+			// The network may have changed while nothing was listening
+			service.requestRefresh("netlink notifications were interrupted")
+		}
+	}()
+
+	return cancelFunc, nil
+}
+
+// openNetlinkSocket opens a netlink socket subscribed to the address and route notifications
+// Returns the socket or error if the subscription fails
+func openNetlinkSocket() (int, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return -1, err
+	}
+
+	address := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR | unix.RTMGRP_IPV4_ROUTE | unix.RTMGRP_IPV6_ROUTE,
+	}
+
+	if err = unix.Bind(fd, address); err != nil {
+		unix.Close(fd)
+
+		return -1, err
+	}
+
+	// Reads time out regularly, so the loop notices it is stopped without closing the socket under a blocked read
+	if err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		unix.Close(fd)
+
+		return -1, err
+	}
+
+	return fd, nil
+}
+
+// readNetworkChanges reads the netlink notifications from the given socket and requests a refresh for the ones
+// that changed the addresses of a WAN interface or the default routes, which can change the public IP address of
+// the node
+// Returns nil once the context is done or error if the socket can not be read anymore
+func (service *cronService) readNetworkChanges(ctx context.Context, fd int) error {
+	buffer := make([]byte, unix.Getpagesize()*4)
+	snapshot := service.newNetworkSnapshot()
+
+	for ctx.Err() == nil {
+		length, _, err := unix.Recvfrom(fd, buffer, 0)
+		if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+			continue
+		}
+
+		// The socket buffer overflowed during a burst of notifications, so some of them were dropped and may have
+		// been the ones that matter
+		if errors.Is(err, unix.ENOBUFS) {
+			service.requestRefresh("netlink notifications were dropped")
+			snapshot = service.newNetworkSnapshot()
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		messages, err := syscall.ParseNetlinkMessage(buffer[:length])
+		if err != nil {
+			service.logger.Debug("Failed to parse the netlink notifications", zap.Error(err))
+
+			continue
+		}
+
+		for _, message := range messages {
+			if reason, ok := service.getNetworkChange(message, snapshot); ok {
+				service.requestRefresh(reason)
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitForNetworkWatcher waits for the given backoff
+// Returns false if the context is done first
+func waitForNetworkWatcher(ctx context.Context, backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// getNetworkChange determines whether the given netlink notification changed the addresses of a WAN interface or
+// the default routes of the node, compared with the given snapshot, and updates the snapshot if it did
+// Returns the description of the change and true if it changed, otherwise false
+func (service *cronService) getNetworkChange(message syscall.NetlinkMessage, snapshot *networkSnapshot) (string, bool) {
+	switch message.Header.Type {
+	case unix.RTM_NEWADDR, unix.RTM_DELADDR:
+		if len(message.Data) < unix.SizeofIfAddrmsg {
+			return "", false
+		}
+
+		addressMessage := (*unix.IfAddrmsg)(unsafe.Pointer(&message.Data[0]))
+
+		// Link local addresses, e.g. the IPv6 fe80::/10 ones, are never used to reach the internet
+		if addressMessage.Scope != unix.RT_SCOPE_UNIVERSE {
+			return "", false
+		}
+
+		// The interface is already gone when its last address is deleted with it, which is still a change
+		networkInterface, err := net.InterfaceByIndex(int(addressMessage.Index))
+		if err != nil {
+			delete(snapshot.addresses, int(addressMessage.Index))
+
+			return "address of a removed interface changed", true
+		}
+
+		if !service.isWanInterface(networkInterface.Name) {
+			return "", false
+		}
+
+		addresses, err := getInterfaceAddresses(networkInterface)
+		if err != nil {
+			return "address of " + networkInterface.Name + " changed", true
+		}
+
+		if previousAddresses, ok := snapshot.addresses[networkInterface.Index]; ok && previousAddresses == addresses {
+			return "", false
+		}
+
+		snapshot.addresses[networkInterface.Index] = addresses
+
+		return "address of " + networkInterface.Name + " changed", true
+	case unix.RTM_NEWROUTE, unix.RTM_DELROUTE:
+		if len(message.Data) < unix.SizeofRtMsg {
+			return "", false
+		}
+
+		if !isDefaultRoute((*unix.RtMsg)(unsafe.Pointer(&message.Data[0]))) {
+			return "", false
+		}
+
+		defaultRoutes, err := getDefaultRoutes()
+		if err != nil {
+			return "default route changed", true
+		}
+
+		if defaultRoutes == snapshot.defaultRoutes {
+			return "", false
+		}
+
+		snapshot.defaultRoutes = defaultRoutes
+
+		return "default route changed", true
+	default:
+		return "", false
+	}
+}
+
+// newNetworkSnapshot returns the current addresses of the WAN interfaces and default routes of the node. What can
+// not be read is left out, so the first notification about it requests a refresh.
+func (service *cronService) newNetworkSnapshot() *networkSnapshot {
+	snapshot := &networkSnapshot{addresses: map[int]string{}}
+
+	networkInterfaces, err := net.Interfaces()
+	if err != nil {
+		service.logger.Debug("Failed to list the network interfaces", zap.Error(err))
+	}
+
+	for index := range networkInterfaces {
+		if !service.isWanInterface(networkInterfaces[index].Name) {
+			continue
+		}
+
+		if addresses, err := getInterfaceAddresses(&networkInterfaces[index]); err == nil {
+			snapshot.addresses[networkInterfaces[index].Index] = addresses
+		}
+	}
+
+	if snapshot.defaultRoutes, err = getDefaultRoutes(); err != nil {
+		service.logger.Debug("Failed to list the default routes", zap.Error(err))
+	}
+
+	return snapshot
+}
+
+// getInterfaceAddresses returns the sorted global unicast addresses of the given network interface
+// Returns the addresses or error if they can not be listed
+func getInterfaceAddresses(networkInterface *net.Interface) (string, error) {
+	interfaceAddresses, err := networkInterface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	addresses := []string{}
+
+	for _, interfaceAddress := range interfaceAddresses {
+		if ipNet, ok := interfaceAddress.(*net.IPNet); ok && ipNet.IP.IsGlobalUnicast() {
+			addresses = append(addresses, ipNet.String())
+		}
+	}
+
+	sort.Strings(addresses)
+
+	return strings.Join(addresses, ","), nil
+}
+
+// getDefaultRoutes returns the sorted gateways, output interfaces and metrics of the default routes of the node
+// Returns the default routes or error if they can not be listed
+func getDefaultRoutes() (string, error) {
+	data, err := syscall.NetlinkRIB(unix.RTM_GETROUTE, unix.AF_UNSPEC)
+	if err != nil {
+		return "", err
+	}
+
+	messages, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return "", err
+	}
+
+	defaultRoutes := []string{}
+
+	for index := range messages {
+		if messages[index].Header.Type != unix.RTM_NEWROUTE || len(messages[index].Data) < unix.SizeofRtMsg {
+			continue
+		}
+
+		routeMessage := (*unix.RtMsg)(unsafe.Pointer(&messages[index].Data[0]))
+		if !isDefaultRoute(routeMessage) {
+			continue
+		}
+
+		attributes, err := syscall.ParseNetlinkRouteAttr(&messages[index])
+		if err != nil {
+			return "", err
+		}
+
+		// The integer attributes are in the byte order of the node
+		gateway, outputInterface, metric := "", "", ""
+
+		for _, attribute := range attributes {
+			switch attribute.Attr.Type {
+			case unix.RTA_GATEWAY:
+				gateway = net.IP(attribute.Value).String()
+			case unix.RTA_OIF:
+				if len(attribute.Value) == 4 {
+					outputInterface = strconv.Itoa(int(*(*uint32)(unsafe.Pointer(&attribute.Value[0]))))
+				}
+			case unix.RTA_PRIORITY:
+				if len(attribute.Value) == 4 {
+					metric = strconv.Itoa(int(*(*uint32)(unsafe.Pointer(&attribute.Value[0]))))
+				}
+			}
+		}
+
+		defaultRoutes = append(
+			defaultRoutes,
+			strconv.Itoa(int(routeMessage.Family))+" "+gateway+" "+outputInterface+" "+metric)
+	}
+
+	sort.Strings(defaultRoutes)
+
+	return strings.Join(defaultRoutes, ","), nil
+}
+
+// isDefaultRoute determines whether the given route is a default route of the main routing table
+func isDefaultRoute(routeMessage *unix.RtMsg) bool {
+	return routeMessage.Dst_len == 0 && routeMessage.Table == unix.RT_TABLE_MAIN && routeMessage.Type == unix.RTN_UNICAST
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package ipgeolocation
+
+import (
+	commonErrors "github.com/micro-business/go-core/system/errors"
+)
+
+// watchNetworkChanges is only supported on Linux, where the netlink notifications are available
+// Returns error as watching network changes is not supported on this platform
+func (service *cronService) watchNetworkChanges() (func(), error) {
+	return nil, commonErrors.NewUnknownError("Watching network changes is only supported on Linux")
+}
@@ -0,0 +1,194 @@
+package ipgeolocation
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/pkg/nodegeolocation"
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// updateNodeGeolocation creates or updates the NodeGeolocation custom resource of the node with the resolved
+// public IP address and geolocation details, moving the previous location into the history if it changed
+func (service *cronService) updateNodeGeolocation(
+	ctx context.Context,
+	node *v1.Node,
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) error {
+	client := service.dynamicClient.Resource(nodegeolocation.GroupVersionResource)
+
+	start := time.Now()
+	object, err := client.Get(ctx, node.Name, metav1.GetOptions{})
+	observeKubernetesCall("get_node_geolocation", start, err)
+
+	if errors.IsNotFound(err) {
+		start = time.Now()
+		object, err = client.Create(ctx, newNodeGeolocation(node), metav1.CreateOptions{})
+		observeKubernetesCall("create_node_geolocation", start, err)
+
+		if err != nil {
+			service.logger.Error(
+				"Failed to create NodeGeolocation",
+				zap.String("runningNodeName", service.runningNodeName),
+				zap.Error(err))
+
+			return err
+		}
+	} else if err != nil {
+		service.logger.Error(
+			"Failed to retrieve NodeGeolocation",
+			zap.String("runningNodeName", service.runningNodeName),
+			zap.Error(err))
+
+		return err
+	}
+
+	nodeGeolocation := nodegeolocation.NodeGeolocation{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, &nodeGeolocation); err != nil {
+		service.logger.Error(
+			"Failed to convert NodeGeolocation",
+			zap.String("runningNodeName", service.runningNodeName),
+			zap.Error(err))
+
+		return err
+	}
+
+	if !service.updateNodeGeolocationStatus(&nodeGeolocation.Status, primaryGeolocationDetails, geolocationDetailsByFamily) {
+		service.logger.Debug("NodeGeolocation did not change. Skipping status update.")
+
+		return nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&nodeGeolocation)
+	if err != nil {
+		service.logger.Error(
+			"Failed to convert NodeGeolocation",
+			zap.String("runningNodeName", service.runningNodeName),
+			zap.Error(err))
+
+		return err
+	}
+
+	// The status is a subresource, so it is only persisted through the status endpoint
+	start = time.Now()
+	_, err = client.UpdateStatus(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+	observeKubernetesCall("update_node_geolocation_status", start, err)
+
+	if err != nil {
+		service.logger.Error(
+			"Failed to update NodeGeolocation status",
+			zap.String("runningNodeName", service.runningNodeName),
+			zap.Error(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// updateNodeGeolocationStatus updates the given status with the resolved details. A status that is still in the
+// same place with the same addresses is left untouched, except for the last success time that is moved on the
+// heartbeat interval, so the runs that find nothing new do not write the resource.
+// Returns true if the status changed otherwise returns false
+func (service *cronService) updateNodeGeolocationStatus(
+	status *nodegeolocation.NodeGeolocationStatus,
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) bool {
+	now := metav1.NewTime(time.Now())
+	location := toNodeGeolocationLocation(primaryGeolocationDetails)
+
+	// Families that failed keep the last known address, the same way their labels do
+	ipv4, ipv6 := status.IPv4, status.IPv6
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv4]; ok {
+		ipv4 = geolocationDetails.Ip
+	}
+
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv6]; ok {
+		ipv6 = geolocationDetails.Ip
+	}
+
+	if status.LastSuccessTime != nil &&
+		status.Location.SamePlace(location) &&
+		status.IPv4 == ipv4 &&
+		status.IPv6 == ipv6 &&
+		(service.heartbeatInterval <= 0 || now.Sub(status.LastSuccessTime.Time) < service.heartbeatInterval) {
+		return false
+	}
+
+	if status.LastSuccessTime != nil && !status.Location.SamePlace(location) {
+		firstSeenTime := *status.LastSuccessTime
+		if status.FirstSeenTime != nil {
+			firstSeenTime = *status.FirstSeenTime
+		}
+
+		history := append([]nodegeolocation.HistoryEntry{{
+			Location:      status.Location,
+			FirstSeenTime: firstSeenTime,
+			LastSeenTime:  *status.LastSuccessTime,
+		}}, status.History...)
+
+		if len(history) > service.nodeGeolocationHistorySize {
+			history = history[:service.nodeGeolocationHistorySize]
+		}
+
+		status.History = history
+		status.FirstSeenTime = nil
+	}
+
+	if status.FirstSeenTime == nil {
+		status.FirstSeenTime = &now
+	}
+
+	status.Location = location
+	status.LastSuccessTime = &now
+	status.IPv4 = ipv4
+	status.IPv6 = ipv6
+
+	return true
+}
+
+func newNodeGeolocation(node *v1.Node) *unstructured.Unstructured {
+	nodeGeolocation := &unstructured.Unstructured{}
+	nodeGeolocation.SetAPIVersion(nodegeolocation.GroupVersionResource.GroupVersion().String())
+	nodeGeolocation.SetKind(nodegeolocation.Kind)
+	nodeGeolocation.SetName(node.Name)
+
+	// Owning the resource by the node lets the garbage collector remove it when the node is deleted
+	nodeGeolocation.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "v1",
+		Kind:       "Node",
+		Name:       node.Name,
+		UID:        node.UID,
+	}})
+
+	nodeGeolocation.Object["spec"] = map[string]interface{}{"nodeName": node.Name}
+
+	return nodeGeolocation
+}
+
+func toNodeGeolocationLocation(geolocationDetails *geolocation.GeolocationDetails) nodegeolocation.Location {
+	location := nodegeolocation.Location{
+		Ip:       geolocationDetails.Ip,
+		Hostname: geolocationDetails.Hostname,
+		City:     geolocationDetails.City,
+		Region:   geolocationDetails.Region,
+		Country:  geolocationDetails.Country,
+		Provider: geolocationDetails.Provider,
+		Source:   geolocationDetails.Source,
+	}
+
+	if coordinates := strings.Split(geolocationDetails.Loc, ","); len(coordinates) == 2 {
+		location.Latitude = strings.TrimSpace(coordinates[0])
+		location.Longitude = strings.TrimSpace(coordinates[1])
+	}
+
+	return location
+}
@@ -0,0 +1,88 @@
+package ipgeolocation
+
+import (
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+)
+
+// applyOverride replaces the resolved location with the one operators supplied through the override annotations
+// of the node and marks the source as manual, keeping the resolved public IP address details and the provider they
+// are resolved by. If the public IP address could not be resolved, the override is applied on top of the details
+// currently in the node labels, or on its own without any public IP address details if the node labels have none.
+// Returns the primary details and the details per address family, or nil if there is nothing to update the node with
+func (service *cronService) applyOverride(
+	node *v1.Node,
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) (
+	*geolocation.GeolocationDetails,
+	map[configuration.AddressFamily]*geolocation.GeolocationDetails) {
+	override, err := labels.ParseOverride(node.Annotations)
+	if err != nil {
+		service.logger.Warn("Ignoring the invalid geolocation override annotations", zap.Error(err))
+
+		return primaryGeolocationDetails, geolocationDetailsByFamily
+	}
+
+	if override == nil {
+		return primaryGeolocationDetails, geolocationDetailsByFamily
+	}
+
+	if primaryGeolocationDetails == nil {
+		// Without a known public IP address the override is the only source of the details, the public IP
+		// address labels are then left out as the Ip is empty
+		primaryGeolocationDetails = &geolocation.GeolocationDetails{Provider: labels.ManualProvider}
+
+		if previousNodeLabels, err := labels.Decode(node.Labels); err == nil && previousNodeLabels.Geolocation != nil {
+			primaryGeolocationDetails = fromLabelsGeolocation(previousNodeLabels.Geolocation)
+		}
+
+		geolocationDetailsByFamily = map[configuration.AddressFamily]*geolocation.GeolocationDetails{}
+	}
+
+	overriddenGeolocationDetailsByFamily := map[configuration.AddressFamily]*geolocation.GeolocationDetails{}
+
+	for addressFamily, geolocationDetails := range geolocationDetailsByFamily {
+		overriddenGeolocationDetailsByFamily[addressFamily] = overrideGeolocationDetails(geolocationDetails, override)
+	}
+
+	return overrideGeolocationDetails(primaryGeolocationDetails, override), overriddenGeolocationDetailsByFamily
+}
+
+// overrideGeolocationDetails returns a copy of the given details with the location fields set by the override
+// replaced and the source marked as manual. The provider is left as is, as it still resolved the public IP address
+// details.
+func overrideGeolocationDetails(
+	geolocationDetails *geolocation.GeolocationDetails,
+	override *labels.Override) *geolocation.GeolocationDetails {
+	overridden := *geolocationDetails
+	overridden.Source = labels.ManualSource
+
+	if loc := override.Loc(); loc != "" {
+		overridden.Loc = loc
+	}
+
+	if override.City != "" {
+		overridden.City = override.City
+	}
+
+	if override.Region != "" {
+		overridden.Region = override.Region
+	}
+
+	if override.Country != "" {
+		overridden.Country = override.Country
+	}
+
+	if override.Postal != "" {
+		overridden.Postal = override.Postal
+	}
+
+	if override.Timezone != "" {
+		overridden.Timezone = override.Timezone
+	}
+
+	return &overridden
+}
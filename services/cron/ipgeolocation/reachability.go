@@ -0,0 +1,107 @@
+package ipgeolocation
+
+import (
+	"net"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	v1 "k8s.io/api/core/v1"
+)
+
+var reachabilityGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "edge_core_node_reachability",
+		Help: "Whether the node is directly reachable, behind NAT or behind CGNAT, 1 for the current classification and 0 for the others",
+	},
+	[]string{"reachability"})
+
+var (
+	sharedAddressSpace   = mustParseCIDRs("100.64.0.0/10")
+	privateAddressSpaces = mustParseCIDRs(
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"fc00::/7")
+)
+
+// classifyReachability compares the addresses the node reports in its status with the discovered public IP address
+// Returns one of the labels.Reachability values
+func classifyReachability(node *v1.Node, publicIp string) string {
+	publicAddress := net.ParseIP(publicIp)
+	if publicAddress == nil {
+		return labels.ReachabilityUnknown
+	}
+
+	// The providers only ever see the outermost address, so a shared or private one means another NAT sits beyond
+	// the WAN side of the router the node is behind
+	if containsAddress(sharedAddressSpace, publicAddress) || containsAddress(privateAddressSpaces, publicAddress) {
+		return labels.ReachabilityCGNAT
+	}
+
+	nodeAddresses := []net.IP{}
+
+	for _, address := range node.Status.Addresses {
+		if address.Type != v1.NodeInternalIP && address.Type != v1.NodeExternalIP {
+			continue
+		}
+
+		nodeAddress := net.ParseIP(address.Address)
+		if nodeAddress == nil || (nodeAddress.To4() == nil) != (publicAddress.To4() == nil) {
+			continue
+		}
+
+		if nodeAddress.Equal(publicAddress) {
+			return labels.ReachabilityDirect
+		}
+
+		nodeAddresses = append(nodeAddresses, nodeAddress)
+	}
+
+	if len(nodeAddresses) == 0 {
+		return labels.ReachabilityUnknown
+	}
+
+	for _, nodeAddress := range nodeAddresses {
+		if containsAddress(sharedAddressSpace, nodeAddress) {
+			return labels.ReachabilityCGNAT
+		}
+	}
+
+	return labels.ReachabilityNAT
+}
+
+func setReachabilityMetric(reachability string) {
+	for _, value := range labels.Reachabilities {
+		if value == reachability {
+			reachabilityGauge.WithLabelValues(value).Set(1)
+		} else {
+			reachabilityGauge.WithLabelValues(value).Set(0)
+		}
+	}
+}
+
+func containsAddress(networks []*net.IPNet, address net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := []*net.IPNet{}
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks
+}
@@ -0,0 +1,180 @@
+package ipgeolocation
+
+import (
+	"context"
+	"time"
+
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// initialTrigger starts the first run after the service starts
+	initialTrigger = "initial"
+	// cronTrigger starts the runs of the cron schedule
+	cronTrigger = "cron"
+	// networkChangeTrigger starts the runs after the network of the node changed
+	networkChangeTrigger = "networkChange"
+	// refreshTrigger starts the runs requested through Refresh
+	refreshTrigger = "refresh"
+)
+
+// runCall is a run in progress, or queued to follow it, that other callers can wait for
+type runCall struct {
+	trigger   string
+	startTime time.Time
+	done      chan struct{}
+	result    *RunResult
+}
+
+// Refresh updates the public IP address and geolocation details of the node right away. As a run in progress
+// may have resolved the details before the refresh was requested, a single run is queued to follow it that all
+// the refreshes requested in the meantime wait for. A refresh requested within the refresh minimum interval after
+// the latest run started returns the result of that run instead, so repeated refreshes can not use up the
+// provider quotas.
+// ctx: Mandatory. The reference to the context that bounds how long the caller waits for the run
+// Returns the result of the run or error if the service is not started or the context is done first
+func (service *cronService) Refresh(ctx context.Context) (*RunResult, error) {
+	if !Live {
+		return nil, commonErrors.NewUnknownError("Geolocation Updater service is not started")
+	}
+
+	call := service.startRefresh(time.Now())
+
+	select {
+	case <-call.done:
+		return call.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run runs a geolocation update, or waits for the one in progress or queued, and returns its result
+// trigger: Mandatory. What started the run
+// changedTime: Optional. When the change the run must pick up happened, zero for the scheduled runs
+func (service *cronService) run(trigger string, changedTime time.Time) *RunResult {
+	call := service.startRun(trigger, changedTime)
+	<-call.done
+
+	return call.result
+}
+
+// startRefresh returns the latest run if it started within the refresh minimum interval, otherwise starts a run
+// requestedTime: Mandatory. When the refresh was requested
+// Returns the run the caller can wait for
+func (service *cronService) startRefresh(requestedTime time.Time) *runCall {
+	service.runLock.Lock()
+	lastRun := service.lastRun
+	service.runLock.Unlock()
+
+	if lastRun != nil && requestedTime.Sub(lastRun.startTime) < service.refreshMinInterval {
+		service.logger.Debug(
+			"Geolocation update started within the refresh minimum interval, returning its result",
+			zap.Time("startTime", lastRun.startTime))
+
+		return lastRun
+	}
+
+	return service.startRun(refreshTrigger, requestedTime)
+}
+
+// startRun starts a geolocation update in the background, so the cron schedule, the network changes and the
+// refresh requests never update the node at the same time. A change after the run in progress started queues a
+// single run to follow it, as the run in progress may have resolved the details before the change, otherwise the
+// run in progress is joined.
+// trigger: Mandatory. What started the run
+// changedTime: Optional. When the change the run must pick up happened, zero for the scheduled runs
+// Returns the run the caller can wait for
+func (service *cronService) startRun(trigger string, changedTime time.Time) *runCall {
+	service.runLock.Lock()
+	defer service.runLock.Unlock()
+
+	if service.currentRun == nil {
+		call := &runCall{trigger: trigger, done: make(chan struct{})}
+		service.executeRun(call)
+
+		return call
+	}
+
+	if !changedTime.After(service.currentRun.startTime) {
+		service.logger.Debug("Geolocation update is already in progress, waiting for it", zap.String("trigger", trigger))
+
+		return service.currentRun
+	}
+
+	if service.nextRun == nil {
+		service.logger.Debug(
+			"Geolocation update is already in progress, queueing another one to follow it",
+			zap.String("trigger", trigger))
+
+		service.nextRun = &runCall{trigger: trigger, done: make(chan struct{})}
+	}
+
+	return service.nextRun
+}
+
+// executeRun runs the given geolocation update in the background and then the one queued to follow it, if any.
+// The run lock must be held.
+func (service *cronService) executeRun(call *runCall) {
+	call.startTime = time.Now()
+	service.currentRun = call
+	service.lastRun = call
+
+	go func() {
+		call.result = service.updateGeolocation(call.trigger)
+		service.recordRun(call.result)
+		service.recordRunOutcome(call.result)
+
+		service.runLock.Lock()
+		service.currentRun = nil
+
+		if nextRun := service.nextRun; nextRun != nil {
+			service.nextRun = nil
+			service.executeRun(nextRun)
+		}
+
+		service.runLock.Unlock()
+
+		close(call.done)
+	}()
+}
+
+func newRunResult(trigger string) *RunResult {
+	return &RunResult{
+		Trigger:   trigger,
+		StartTime: time.Now(),
+		Outcome:   RunSucceeded,
+		Stages:    []StageResult{},
+	}
+}
+
+// addStage records the outcome of a stage, failing the run if the stage failed
+func (result *RunResult) addStage(stage string, outcome string, message string) {
+	result.Stages = append(result.Stages, StageResult{Stage: stage, Outcome: outcome, Message: message})
+
+	if outcome == RunFailed {
+		result.Outcome = RunFailed
+	}
+}
+
+// addStageError records the outcome of a stage that succeeded if the given error is nil, otherwise failed
+func (result *RunResult) addStageError(stage string, err error) {
+	if err != nil {
+		result.addStage(stage, RunFailed, err.Error())
+
+		return
+	}
+
+	result.addStage(stage, RunSucceeded, "")
+}
+
+// finish marks the run as finished with the given outcome, unless a stage already failed it
+func (result *RunResult) finish(outcome string) *RunResult {
+	if result.Outcome != RunFailed {
+		result.Outcome = outcome
+	}
+
+	result.FinishTime = time.Now()
+
+	return result
+}
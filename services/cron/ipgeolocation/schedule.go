@@ -0,0 +1,62 @@
+package ipgeolocation
+
+import (
+	"hash/fnv"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+)
+
+// spreadSchedule shifts the runs of the cron schedule by the deterministic offset of the node plus a random jitter,
+// so the runs of the nodes of a fleet are spread evenly instead of all starting at the same second
+type spreadSchedule struct {
+	schedule  cron.Schedule
+	offset    time.Duration
+	maxJitter time.Duration
+	jitter    func() time.Duration
+	last      time.Time
+}
+
+// Next returns the next time the job should run after the given time
+func (schedule *spreadSchedule) Next(t time.Time) time.Time {
+	// Removing the offset maps the time back onto the original schedule, so the shift never skips or repeats a run.
+	// Intervals like @every are relative to the start instead, so they are shifted by leaving the offset in once.
+	from := t.Add(-schedule.offset)
+	if _, ok := schedule.schedule.(cron.ConstantDelaySchedule); ok && schedule.last.IsZero() {
+		from = t
+	}
+
+	// The jitter of the run that just finished is not carried over, otherwise intervals like @every would drift
+	if !schedule.last.IsZero() && !from.Before(schedule.last) && from.Sub(schedule.last) <= schedule.maxJitter {
+		from = schedule.last
+	}
+
+	schedule.last = schedule.schedule.Next(from)
+
+	return schedule.last.Add(schedule.offset + schedule.jitter())
+}
+
+// getScheduleOffset derives the offset of the node within the given spread window from the node name, so the offset
+// stays the same across restarts while the nodes of a fleet are spread evenly over the window
+func getScheduleOffset(nodeName string, spread time.Duration) time.Duration {
+	if spread <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(nodeName))
+
+	return time.Duration(hash.Sum64() % uint64(spread))
+}
+
+// getJitter returns a random delay up to the configured jitter
+func (service *cronService) getJitter() time.Duration {
+	if service.schedulePolicy.Jitter <= 0 {
+		return 0
+	}
+
+	service.randomLock.Lock()
+	defer service.randomLock.Unlock()
+
+	return time.Duration(service.random.Int63n(int64(service.schedulePolicy.Jitter)))
+}
@@ -0,0 +1,66 @@
+package ipgeolocation
+
+import (
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/state"
+	"go.uber.org/zap"
+)
+
+// loadFreshState returns the saved details if they were resolved within the freshness window, so the providers
+// do not need to be called, e.g. right after the pod restarted
+// Returns the primary details and the details per address family, or nil if there is no fresh saved state
+func (service *cronService) loadFreshState() (*geolocation.GeolocationDetails, map[configuration.AddressFamily]*geolocation.GeolocationDetails) {
+	if service.stateFreshnessWindow <= 0 {
+		return nil, nil
+	}
+
+	savedState, err := service.stateStore.Load()
+	if err != nil || savedState == nil || savedState.Geolocation == nil {
+		return nil, nil
+	}
+
+	age := time.Since(savedState.UpdatedTime)
+	if age >= service.stateFreshnessWindow {
+		return nil, nil
+	}
+
+	service.logger.Info("Using the saved geolocation details as they are still fresh", zap.Duration("age", age))
+
+	return getSavedGeolocationDetails(savedState)
+}
+
+// getSavedGeolocationDetails returns the primary details and the details per address family of the given state
+func getSavedGeolocationDetails(
+	savedState *state.State) (*geolocation.GeolocationDetails, map[configuration.AddressFamily]*geolocation.GeolocationDetails) {
+	geolocationDetailsByFamily := map[configuration.AddressFamily]*geolocation.GeolocationDetails{}
+
+	if savedState.IPv4 != nil {
+		geolocationDetailsByFamily[configuration.IPv4] = savedState.IPv4
+	}
+
+	if savedState.IPv6 != nil {
+		geolocationDetailsByFamily[configuration.IPv6] = savedState.IPv6
+	}
+
+	return savedState.Geolocation, geolocationDetailsByFamily
+}
+
+// saveState saves the resolved details, failures are only logged as the saved state is a best effort cache
+func (service *cronService) saveState(
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) {
+	// The quota usage the geolocation providers keep in the same state is left untouched
+	err := service.stateStore.Update(func(savedState *state.State) {
+		savedState.UpdatedTime = time.Now()
+		savedState.Geolocation = primaryGeolocationDetails
+		savedState.IPv4 = geolocationDetailsByFamily[configuration.IPv4]
+		savedState.IPv6 = geolocationDetailsByFamily[configuration.IPv6]
+	})
+
+	if err != nil {
+		service.logger.Warn("Failed to save the geolocation state", zap.Error(err))
+	}
+}
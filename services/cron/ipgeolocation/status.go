@@ -0,0 +1,69 @@
+package ipgeolocation
+
+import (
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+)
+
+// GetStatus returns the public IP address and geolocation details the updater last applied and the results of
+// its latest runs, without calling the providers or the Kubernetes API server
+// Returns the status of the updater
+func (service *cronService) GetStatus() Status {
+	service.statusLock.Lock()
+	defer service.statusLock.Unlock()
+
+	// The runs are copied, so the caller can not observe the history changing while it encodes the status
+	status := service.status
+	status.Runs = append([]RunResult{}, service.status.Runs...)
+
+	return status
+}
+
+// recordRun keeps the result of a finished run in the run history, dropping the oldest results beyond the
+// configured history size
+func (service *cronService) recordRun(result *RunResult) {
+	service.statusLock.Lock()
+	defer service.statusLock.Unlock()
+
+	startTime := result.StartTime
+	service.status.LastAttemptTime = &startTime
+
+	if result.Outcome == RunSucceeded {
+		finishTime := result.FinishTime
+		service.status.LastSuccessTime = &finishTime
+	}
+
+	runs := append([]RunResult{*result}, service.status.Runs...)
+	if len(runs) > service.runHistorySize {
+		runs = runs[:service.runHistorySize]
+	}
+
+	service.status.Runs = runs
+}
+
+// recordCurrentGeolocation keeps the details the node was successfully updated with
+func (service *cronService) recordCurrentGeolocation(
+	primaryGeolocationDetails *geolocation.GeolocationDetails,
+	geolocationDetailsByFamily map[configuration.AddressFamily]*geolocation.GeolocationDetails) {
+	service.statusLock.Lock()
+	defer service.statusLock.Unlock()
+
+	service.status.Geolocation = service.toNetworkClassifiedGeolocation(primaryGeolocationDetails)
+	service.status.IPv4 = nil
+	service.status.IPv6 = nil
+	service.status.Latitude = nil
+	service.status.Longitude = nil
+
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv4]; ok {
+		service.status.IPv4 = service.toNetworkClassifiedGeolocation(geolocationDetails)
+	}
+
+	if geolocationDetails, ok := geolocationDetailsByFamily[configuration.IPv6]; ok {
+		service.status.IPv6 = service.toNetworkClassifiedGeolocation(geolocationDetails)
+	}
+
+	if latitude, longitude, ok := parseLoc(primaryGeolocationDetails.Loc); ok {
+		service.status.Latitude = &latitude
+		service.status.Longitude = &longitude
+	}
+}
@@ -0,0 +1,114 @@
+package ipgeolocation
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/decentralized-cloud/edge-core/pkg/labels"
+	"golang.org/x/text/unicode/norm"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// topologyManagedAnnotation lists the topology labels the edge-core set itself, so they can be updated later
+	// while the ones set by a cloud provider or an operator are left untouched
+	topologyManagedAnnotation = "edgecloud9.geolocation.topologyManaged"
+
+	// maxLabelValueLength is the maximum length of a label value
+	maxLabelValueLength = 63
+)
+
+// getTopologyLabels derives the topology region and zone labels from the given geolocation details
+// Returns the topology labels to set, the topology labels to remove and the new value of the topology managed
+// annotation
+func (service *cronService) getTopologyLabels(node *v1.Node, geolocation *labels.Geolocation) (map[string]string, []string, string) {
+	topologyLabels := map[string]string{}
+	removedLabels := []string{}
+
+	if !service.updateTopologyLabels {
+		return topologyLabels, removedLabels, node.Annotations[topologyManagedAnnotation]
+	}
+
+	managed := map[string]bool{}
+
+	for _, key := range strings.Split(node.Annotations[topologyManagedAnnotation], ",") {
+		managed[key] = true
+	}
+
+	templates := map[string]string{
+		v1.LabelTopologyRegion: service.topologyRegionTemplate,
+		v1.LabelTopologyZone:   service.topologyZoneTemplate,
+	}
+
+	managedKeys := []string{}
+
+	for key, template := range templates {
+		if _, ok := node.Labels[key]; ok && !managed[key] {
+			continue
+		}
+
+		value := sanitizeLabelValue(expandTopologyTemplate(template, geolocation))
+		if value == "" {
+			// A label set before stays managed until it is removed, so it is not mistaken for one set by an
+			// operator and left stale
+			if _, ok := node.Labels[key]; ok {
+				removedLabels = append(removedLabels, key)
+				managedKeys = append(managedKeys, key)
+			}
+
+			continue
+		}
+
+		topologyLabels[key] = value
+		managedKeys = append(managedKeys, key)
+	}
+
+	sort.Strings(removedLabels)
+	sort.Strings(managedKeys)
+
+	return topologyLabels, removedLabels, strings.Join(managedKeys, ",")
+}
+
+func expandTopologyTemplate(template string, geolocation *labels.Geolocation) string {
+	return strings.NewReplacer(
+		"{country}", geolocation.Country,
+		"{region}", geolocation.Region,
+		"{city}", geolocation.City,
+		"{postal}", geolocation.Postal).Replace(template)
+}
+
+// sanitizeLabelValue turns the given text into a valid label value, e.g. "BR-São Paulo" into "br-sao-paulo"
+func sanitizeLabelValue(value string) string {
+	builder := strings.Builder{}
+	lastIsSeparator := true
+
+	// Decomposing the text splits accented letters into the letter and the accent, so the accent can be dropped
+	for _, character := range norm.NFD.String(strings.ToLower(value)) {
+		switch {
+		case unicode.Is(unicode.Mn, character):
+			continue
+		case (character >= 'a' && character <= 'z') || (character >= '0' && character <= '9'):
+			builder.WriteRune(character)
+			lastIsSeparator = false
+		case character == '.' || character == '_':
+			if !lastIsSeparator {
+				builder.WriteRune(character)
+				lastIsSeparator = true
+			}
+		default:
+			if !lastIsSeparator {
+				builder.WriteRune('-')
+				lastIsSeparator = true
+			}
+		}
+	}
+
+	sanitized := builder.String()
+	if len(sanitized) > maxLabelValueLength {
+		sanitized = sanitized[:maxLabelValueLength]
+	}
+
+	// Label values must start and end with an alphanumeric character
+	return strings.Trim(sanitized, "-_.")
+}
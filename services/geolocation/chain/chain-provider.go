@@ -0,0 +1,572 @@
+// Package chain implements the geolocation provider that falls back through an ordered list of geolocation providers
+// and optionally only accepts a public IP address a minimum number of them agree on. Failed calls are retried with
+// jittered exponential backoff and every provider has its own circuit breaker per address family.
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/state"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var retriesCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "edge_core_geolocation_provider_retries_total",
+		Help: "Total number of retried calls to the geolocation provider",
+	},
+	[]string{"provider"})
+
+// Provider is a geolocation provider participating in the chain
+type Provider struct {
+	// Name is the name of the provider used in logs and recorded as the provenance of the result
+	Name string
+
+	// Provider is the geolocation provider
+	Provider geolocation.GeolocationProviderContract
+
+	// Timeout is the maximum time allowed for a single attempt to call the provider. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// circuitBreakerKey identifies the circuit breaker of a provider for an address family
+type circuitBreakerKey struct {
+	name          string
+	addressFamily configuration.AddressFamily
+}
+
+type chainProvider struct {
+	logger               *zap.Logger
+	providers            []Provider
+	consensus            int
+	retryPolicy          configuration.RetryPolicy
+	circuitBreakerPolicy configuration.CircuitBreakerPolicy
+	circuitBreakersLock  sync.Mutex
+	circuitBreakers      map[circuitBreakerKey]*circuitBreaker
+	requestBudgets       map[string]*requestBudget
+	stateStore           state.StateStoreContract
+	randomLock           sync.Mutex
+	random               *rand.Rand
+}
+
+// NewChainProvider creates new instance of the chainProvider, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// providers: Mandatory. The ordered list of geolocation providers to try
+// consensus: Mandatory. The minimum number of providers that must agree on the public IP address. Values less than
+// or equal to one disable the consensus mode and the result of the first successful provider is returned.
+// retryPolicy: Mandatory. How failed calls to a provider are retried
+// circuitBreakerPolicy: Mandatory. When the circuit breaker of a provider opens and for how long
+// quotas: Optional. The request quotas of the providers that have one, keyed by the provider name
+// stateStore: Mandatory. Reference to the store that keeps the usage of the request quotas across restarts
+// Returns the new provider or error if something goes wrong
+func NewChainProvider(
+	logger *zap.Logger,
+	providers []Provider,
+	consensus int,
+	retryPolicy configuration.RetryPolicy,
+	circuitBreakerPolicy configuration.CircuitBreakerPolicy,
+	quotas map[string]configuration.ProviderQuota,
+	stateStore state.StateStoreContract) (ChainProviderContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if len(providers) == 0 {
+		return nil, commonErrors.NewArgumentError("providers", "at least one provider is required")
+	}
+
+	for _, provider := range providers {
+		if provider.Provider == nil {
+			return nil, commonErrors.NewArgumentNilError("providers", fmt.Sprintf("provider %s is nil", provider.Name))
+		}
+	}
+
+	if consensus > len(providers) {
+		return nil, commonErrors.NewArgumentError(
+			"consensus",
+			fmt.Sprintf("consensus (%d) can not be more than the number of providers (%d)", consensus, len(providers)))
+	}
+
+	if stateStore == nil {
+		return nil, commonErrors.NewArgumentNilError("stateStore", "stateStore is required")
+	}
+
+	// The providers can still be called without the saved usage, the quotas then start from the full budget
+	savedState, err := stateStore.Load()
+	if err != nil {
+		logger.Warn("Failed to load the saved usage of the geolocation provider quotas", zap.Error(err))
+	}
+
+	requestBudgets := map[string]*requestBudget{}
+
+	for _, provider := range providers {
+		var usage *state.QuotaUsage
+		if savedState != nil {
+			if savedUsage, ok := savedState.Quotas[provider.Name]; ok {
+				usage = &savedUsage
+			}
+		}
+
+		requestBudgets[provider.Name] = newRequestBudget(provider.Name, quotas[provider.Name], usage)
+	}
+
+	return &chainProvider{
+		logger:               logger,
+		providers:            providers,
+		consensus:            consensus,
+		retryPolicy:          retryPolicy,
+		circuitBreakerPolicy: circuitBreakerPolicy,
+		circuitBreakers:      map[circuitBreakerKey]*circuitBreaker{},
+		requestBudgets:       requestBudgets,
+		stateStore:           stateStore,
+		random:               rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// GetCircuitBreakerStates returns the current state of the circuit breakers of the chain. Every provider has
+// a circuit breaker per address family, so a family the node has no public address of does not open the
+// circuit breaker of the other families.
+// Returns the circuit breaker states keyed by the provider name and then by the address family
+func (provider *chainProvider) GetCircuitBreakerStates() map[string]map[string]CircuitBreakerState {
+	provider.circuitBreakersLock.Lock()
+	defer provider.circuitBreakersLock.Unlock()
+
+	states := map[string]map[string]CircuitBreakerState{}
+
+	for key, breaker := range provider.circuitBreakers {
+		if states[key.name] == nil {
+			states[key.name] = map[string]CircuitBreakerState{}
+		}
+
+		states[key.name][key.addressFamily.String()] = breaker.getState()
+	}
+
+	return states
+}
+
+// GetGeolocationDetails returns the node public IP address and geolocation details
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to resolve the node public IP address for
+// Returns the node public IP address and geolocation details or error if something goes wrong
+func (provider *chainProvider) GetGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	if provider.consensus <= 1 {
+		return provider.getFirstGeolocationDetails(ctx, addressFamily)
+	}
+
+	return provider.getAgreedGeolocationDetails(ctx, addressFamily)
+}
+
+// getFirstGeolocationDetails returns the result of the first provider that succeeds
+func (provider *chainProvider) getFirstGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	errs := []error{}
+
+	for index, item := range provider.providers {
+		geolocationDetails, err := provider.callProvider(ctx, item, addressFamily, len(provider.providers)-index)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		geolocationDetails.Provider = item.Name
+
+		return geolocationDetails, nil
+	}
+
+	if throttledErr := getThrottledError(errs); throttledErr != nil {
+		return nil, throttledErr
+	}
+
+	if err := getLocalDialError(errs); err != nil {
+		provider.logger.Debug(
+			"No geolocation provider is reachable over the address family",
+			zap.String("addressFamily", addressFamily.String()))
+
+		return nil, err
+	}
+
+	provider.logger.Error("All geolocation providers failed", zap.String("addressFamily", addressFamily.String()))
+
+	return nil, commonErrors.NewUnknownError(fmt.Sprintf("all geolocation providers failed for %s", addressFamily))
+}
+
+// getAgreedGeolocationDetails returns the result of the first provider that returned the public IP address
+// at least consensus number of providers agree on
+func (provider *chainProvider) getAgreedGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	results := map[string][]*geolocation.GeolocationDetails{}
+	names := map[string][]string{}
+	errs := []error{}
+
+	for index, item := range provider.providers {
+		geolocationDetails, err := provider.callProvider(ctx, item, addressFamily, len(provider.providers)-index)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		ip := net.ParseIP(geolocationDetails.Ip).String()
+
+		results[ip] = append(results[ip], geolocationDetails)
+		names[ip] = append(names[ip], item.Name)
+
+		if len(results[ip]) >= provider.consensus {
+			agreedGeolocationDetails := *results[ip][0]
+			agreedGeolocationDetails.Provider = strings.Join(names[ip], ",")
+
+			return &agreedGeolocationDetails, nil
+		}
+	}
+
+	if len(results) == 0 {
+		if throttledErr := getThrottledError(errs); throttledErr != nil {
+			return nil, throttledErr
+		}
+
+		if err := getLocalDialError(errs); err != nil {
+			provider.logger.Debug(
+				"No geolocation provider is reachable over the address family",
+				zap.String("addressFamily", addressFamily.String()))
+
+			return nil, err
+		}
+	}
+
+	provider.logger.Error(
+		"Geolocation providers did not agree on the public IP address",
+		zap.String("addressFamily", addressFamily.String()),
+		zap.Int("consensus", provider.consensus),
+		zap.Any("votes", names))
+
+	return nil, commonErrors.NewUnknownError(
+		fmt.Sprintf("no public IP address was agreed on by at least %d geolocation providers", provider.consensus))
+}
+
+// callProvider calls the given provider through its circuit breaker, retrying failed attempts with jittered
+// exponential backoff as long as its share of the time left allows
+// remainingProviders: Mandatory. The number of providers left to call including the given one, the time left is
+// shared evenly between them so a provider that does not respond still leaves time for the ones after it
+func (provider *chainProvider) callProvider(
+	ctx context.Context,
+	item Provider,
+	addressFamily configuration.AddressFamily,
+	remainingProviders int) (*geolocation.GeolocationDetails, error) {
+	maxRetries := provider.retryPolicy.MaxRetries
+
+	breaker, hasBreaker := provider.getCircuitBreaker(item.Name, addressFamily)
+	if hasBreaker {
+		allowed, trial := breaker.allow()
+		if !allowed {
+			provider.logger.Debug(
+				"Geolocation provider circuit breaker is open",
+				zap.String("provider", item.Name),
+				zap.String("addressFamily", addressFamily.String()))
+
+			return nil, commonErrors.NewUnknownError(fmt.Sprintf("circuit breaker of %s for %s is open", item.Name, addressFamily))
+		}
+
+		// A single attempt is enough to find out whether the provider recovered
+		if trial {
+			maxRetries = 0
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && remainingProviders > 1 {
+		var cancelFunc context.CancelFunc
+
+		ctx, cancelFunc = context.WithTimeout(ctx, time.Until(deadline)/time.Duration(remainingProviders))
+		defer cancelFunc()
+	}
+
+	geolocationDetails, err := provider.callProviderWithRetries(ctx, item, addressFamily, maxRetries)
+
+	var throttledErr *geolocation.ThrottledError
+
+	if hasBreaker {
+		// Neither a throttled provider nor one the node has no route to was actually called
+		if errors.As(err, &throttledErr) || isLocalDialError(err) {
+			breaker.cancel()
+		} else if err == nil {
+			breaker.recordSuccess()
+		} else if breaker.recordFailure() {
+			provider.logger.Warn(
+				"Geolocation provider circuit breaker opened",
+				zap.String("provider", item.Name),
+				zap.String("addressFamily", addressFamily.String()))
+		}
+	}
+
+	return geolocationDetails, err
+}
+
+// getCircuitBreaker returns the circuit breaker of the given provider for the given address family, creating it
+// on first use. Returns false if the circuit breakers are disabled.
+func (provider *chainProvider) getCircuitBreaker(name string, addressFamily configuration.AddressFamily) (*circuitBreaker, bool) {
+	if provider.circuitBreakerPolicy.FailureThreshold <= 0 {
+		return nil, false
+	}
+
+	provider.circuitBreakersLock.Lock()
+	defer provider.circuitBreakersLock.Unlock()
+
+	key := circuitBreakerKey{name: name, addressFamily: addressFamily}
+
+	breaker, ok := provider.circuitBreakers[key]
+	if !ok {
+		breaker = newCircuitBreaker(
+			name,
+			addressFamily,
+			provider.circuitBreakerPolicy.FailureThreshold,
+			provider.circuitBreakerPolicy.OpenDuration)
+		provider.circuitBreakers[key] = breaker
+	}
+
+	return breaker, true
+}
+
+func (provider *chainProvider) callProviderWithRetries(
+	ctx context.Context,
+	item Provider,
+	addressFamily configuration.AddressFamily,
+	maxRetries int) (*geolocation.GeolocationDetails, error) {
+	budget := provider.requestBudgets[item.Name]
+
+	var err error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			retriesCounter.WithLabelValues(item.Name).Inc()
+
+			if !provider.waitBeforeRetry(ctx, attempt) {
+				break
+			}
+		}
+
+		if ok, until := budget.allow(time.Now()); !ok {
+			provider.logger.Debug(
+				"Geolocation provider is throttled",
+				zap.String("provider", item.Name),
+				zap.Time("until", until))
+
+			// The failure of an earlier attempt is the more useful error to report
+			if err == nil {
+				err = &geolocation.ThrottledError{Provider: item.Name, Until: until}
+			}
+
+			break
+		}
+
+		var geolocationDetails *geolocation.GeolocationDetails
+
+		geolocationDetails, err = provider.callProviderOnce(ctx, item, addressFamily)
+		if err == nil {
+			return geolocationDetails, nil
+		}
+
+		// Expected for the address families the node has no public address of, e.g. IPv6 on IPv4 only nodes, which
+		// no retry can fix
+		if isLocalDialError(err) {
+			provider.logger.Debug(
+				"Geolocation provider is not reachable over the address family",
+				zap.String("provider", item.Name),
+				zap.String("addressFamily", addressFamily.String()),
+				zap.Error(err))
+
+			return nil, err
+		}
+
+		provider.logger.Warn(
+			"Geolocation provider failed",
+			zap.String("provider", item.Name),
+			zap.String("addressFamily", addressFamily.String()),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err))
+
+		// A provider that did not respond in time is likely unreachable, e.g. blocked by a firewall, so the time is
+		// better spent on the next provider than on waiting for it again
+		if ctx.Err() != nil || isTimeout(err) {
+			break
+		}
+
+		var statusErr *geolocation.HttpStatusError
+		if errors.As(err, &statusErr) {
+			// The provider said how long to wait, which is usually longer than the retries can wait for
+			if statusErr.RetryAfter > 0 {
+				budget.block(time.Now().Add(statusErr.RetryAfter))
+				provider.saveRequestBudget(budget)
+
+				break
+			}
+
+			if !statusErr.Retryable() {
+				break
+			}
+		}
+	}
+
+	return nil, err
+}
+
+// saveRequestBudget saves the usage of the given request budget, failures are only logged as the budget keeps
+// being enforced in memory
+func (provider *chainProvider) saveRequestBudget(budget *requestBudget) {
+	usage := budget.getUsage()
+
+	err := provider.stateStore.Update(func(savedState *state.State) {
+		if savedState.Quotas == nil {
+			savedState.Quotas = map[string]state.QuotaUsage{}
+		}
+
+		savedState.Quotas[budget.name] = usage
+	})
+
+	if err != nil {
+		provider.logger.Warn(
+			"Failed to save the usage of the geolocation provider quota",
+			zap.String("provider", budget.name),
+			zap.Error(err))
+	}
+}
+
+// waitBeforeRetry waits a random time up to the exponentially growing backoff of the given attempt, so nodes
+// that failed at the same time do not retry at the same time. Returns false if the context is done first.
+func (provider *chainProvider) waitBeforeRetry(ctx context.Context, attempt int) bool {
+	backoff := provider.retryPolicy.InitialBackoff << uint(attempt-1)
+	if backoff <= 0 || backoff > provider.retryPolicy.MaxBackoff {
+		backoff = provider.retryPolicy.MaxBackoff
+	}
+
+	if backoff <= 0 {
+		return ctx.Err() == nil
+	}
+
+	provider.randomLock.Lock()
+	wait := time.Duration(provider.random.Int63n(int64(backoff) + 1))
+	provider.randomLock.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (provider *chainProvider) callProviderOnce(
+	ctx context.Context,
+	item Provider,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	if item.Timeout > 0 {
+		var cancelFunc context.CancelFunc
+
+		ctx, cancelFunc = context.WithTimeout(ctx, item.Timeout)
+		defer cancelFunc()
+	}
+
+	start := time.Now()
+	geolocationDetails, err := item.Provider.GetGeolocationDetails(ctx, addressFamily)
+
+	// Only the requests the provider answered count towards its quota, not the ones that failed locally, e.g.
+	// because the node has no route to the provider over the address family
+	var statusErr *geolocation.HttpStatusError
+	if err == nil || errors.As(err, &statusErr) {
+		budget := provider.requestBudgets[item.Name]
+		budget.record(start)
+
+		if budget.quota.Requests > 0 {
+			provider.saveRequestBudget(budget)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Providers that can not be restricted to an address family may still answer over the other one
+	if !addressFamily.Matches(net.ParseIP(geolocationDetails.Ip)) {
+		return nil, commonErrors.NewUnknownError(
+			fmt.Sprintf("%s returned %s which is not an %s address", item.Name, geolocationDetails.Ip, addressFamily))
+	}
+
+	return geolocationDetails, nil
+}
+
+// isLocalDialError determines whether the given error means the request never left the node, e.g. because the
+// node has no route or no address of the address family
+func isLocalDialError(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "dial" {
+		return false
+	}
+
+	var addrErr *net.AddrError
+
+	return errors.Is(err, syscall.ENETUNREACH) ||
+		errors.Is(err, syscall.EADDRNOTAVAIL) ||
+		errors.Is(err, syscall.EAFNOSUPPORT) ||
+		errors.As(err, &addrErr)
+}
+
+// getLocalDialError returns the first of the given errors if all of them are local dial errors, which means the
+// node can not reach any provider over the address family
+func getLocalDialError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	for _, err := range errs {
+		if !isLocalDialError(err) {
+			return nil
+		}
+	}
+
+	return errs[0]
+}
+
+// isTimeout determines whether the given error means the provider did not respond in time
+func isTimeout(err error) bool {
+	var netErr net.Error
+
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// getThrottledError returns the throttled error that ends first if all the given errors are throttled errors,
+// which means no provider was actually called
+func getThrottledError(errs []error) *geolocation.ThrottledError {
+	var earliest *geolocation.ThrottledError
+
+	for _, err := range errs {
+		var throttledErr *geolocation.ThrottledError
+		if !errors.As(err, &throttledErr) {
+			return nil
+		}
+
+		if earliest == nil || throttledErr.Until.Before(earliest.Until) {
+			earliest = throttledErr
+		}
+	}
+
+	return earliest
+}
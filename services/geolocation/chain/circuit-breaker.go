@@ -0,0 +1,141 @@
+package chain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CircuitBreakerState is the state of the circuit breaker of a geolocation provider
+type CircuitBreakerState int
+
+const (
+	// CircuitBreakerClosed lets all calls to the provider through
+	CircuitBreakerClosed CircuitBreakerState = iota
+	// CircuitBreakerHalfOpen lets a single trial call to the provider through to find out whether it recovered
+	CircuitBreakerHalfOpen
+	// CircuitBreakerOpen rejects all calls to the provider without calling it
+	CircuitBreakerOpen
+)
+
+// String returns the name of the circuit breaker state
+func (state CircuitBreakerState) String() string {
+	switch state {
+	case CircuitBreakerHalfOpen:
+		return "half-open"
+	case CircuitBreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+var circuitBreakerStateGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "edge_core_geolocation_provider_circuit_breaker_state",
+		Help: "State of the circuit breaker of the geolocation provider, 0 closed, 1 half-open and 2 open",
+	},
+	[]string{"provider", "address_family"})
+
+type circuitBreaker struct {
+	lock             sync.Mutex
+	name             string
+	addressFamily    configuration.AddressFamily
+	failureThreshold int
+	openDuration     time.Duration
+	state            CircuitBreakerState
+	failures         int
+	openedAt         time.Time
+}
+
+func newCircuitBreaker(
+	name string,
+	addressFamily configuration.AddressFamily,
+	failureThreshold int,
+	openDuration time.Duration) *circuitBreaker {
+	breaker := &circuitBreaker{
+		name:             name,
+		addressFamily:    addressFamily,
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+	}
+
+	circuitBreakerStateGauge.WithLabelValues(name, addressFamily.String()).Set(float64(CircuitBreakerClosed))
+
+	return breaker
+}
+
+// allow determines whether a call to the provider can go through, moving an open circuit breaker to half-open
+// once the open duration has passed
+// Returns true if the call can go through, and true if it is the trial call of a half-open circuit breaker
+func (breaker *circuitBreaker) allow() (bool, bool) {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	switch breaker.state {
+	case CircuitBreakerClosed:
+		return true, false
+	case CircuitBreakerOpen:
+		if time.Since(breaker.openedAt) < breaker.openDuration {
+			return false, false
+		}
+
+		breaker.setState(CircuitBreakerHalfOpen)
+
+		return true, true
+	default:
+		// A trial call is already in flight
+		return false, false
+	}
+}
+
+func (breaker *circuitBreaker) recordSuccess() {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	breaker.failures = 0
+	breaker.setState(CircuitBreakerClosed)
+}
+
+// cancel returns a half-open circuit breaker to open when the trial call was not made, so the next call is let
+// through as the trial instead
+func (breaker *circuitBreaker) cancel() {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	if breaker.state == CircuitBreakerHalfOpen {
+		breaker.setState(CircuitBreakerOpen)
+	}
+}
+
+// recordFailure counts a failed call and returns true if the circuit breaker opened because of it
+func (breaker *circuitBreaker) recordFailure() bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	breaker.failures++
+
+	if breaker.state == CircuitBreakerHalfOpen || breaker.failures >= breaker.failureThreshold {
+		breaker.openedAt = time.Now()
+		breaker.setState(CircuitBreakerOpen)
+
+		return true
+	}
+
+	return false
+}
+
+func (breaker *circuitBreaker) getState() CircuitBreakerState {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	return breaker.state
+}
+
+func (breaker *circuitBreaker) setState(state CircuitBreakerState) {
+	breaker.state = state
+	circuitBreakerStateGauge.WithLabelValues(breaker.name, breaker.addressFamily.String()).Set(float64(state))
+}
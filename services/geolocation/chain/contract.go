@@ -0,0 +1,16 @@
+package chain
+
+import (
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+)
+
+// ChainProviderContract declares the methods to be implemented by the chain geolocation provider
+type ChainProviderContract interface {
+	geolocation.GeolocationProviderContract
+
+	// GetCircuitBreakerStates returns the current state of the circuit breakers of the chain. Every provider has
+	// a circuit breaker per address family, so a family the node has no public address of does not open the
+	// circuit breaker of the other families.
+	// Returns the circuit breaker states keyed by the provider name and then by the address family
+	GetCircuitBreakerStates() map[string]map[string]CircuitBreakerState
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/geolocation/chain/contract.go
+
+// Package mock_chain is a generated GoMock package.
+package mock_chain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	configuration "github.com/decentralized-cloud/edge-core/services/configuration"
+	geolocation "github.com/decentralized-cloud/edge-core/services/geolocation"
+	chain "github.com/decentralized-cloud/edge-core/services/geolocation/chain"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChainProviderContract is a mock of ChainProviderContract interface.
+type MockChainProviderContract struct {
+	ctrl     *gomock.Controller
+	recorder *MockChainProviderContractMockRecorder
+}
+
+// MockChainProviderContractMockRecorder is the mock recorder for MockChainProviderContract.
+type MockChainProviderContractMockRecorder struct {
+	mock *MockChainProviderContract
+}
+
+// NewMockChainProviderContract creates a new mock instance.
+func NewMockChainProviderContract(ctrl *gomock.Controller) *MockChainProviderContract {
+	mock := &MockChainProviderContract{ctrl: ctrl}
+	mock.recorder = &MockChainProviderContractMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChainProviderContract) EXPECT() *MockChainProviderContractMockRecorder {
+	return m.recorder
+}
+
+// GetCircuitBreakerStates mocks base method.
+func (m *MockChainProviderContract) GetCircuitBreakerStates() map[string]map[string]chain.CircuitBreakerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCircuitBreakerStates")
+	ret0, _ := ret[0].(map[string]map[string]chain.CircuitBreakerState)
+	return ret0
+}
+
+// GetCircuitBreakerStates indicates an expected call of GetCircuitBreakerStates.
+func (mr *MockChainProviderContractMockRecorder) GetCircuitBreakerStates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCircuitBreakerStates", reflect.TypeOf((*MockChainProviderContract)(nil).GetCircuitBreakerStates))
+}
+
+// GetGeolocationDetails mocks base method.
+func (m *MockChainProviderContract) GetGeolocationDetails(ctx context.Context, addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationDetails", ctx, addressFamily)
+	ret0, _ := ret[0].(*geolocation.GeolocationDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationDetails indicates an expected call of GetGeolocationDetails.
+func (mr *MockChainProviderContractMockRecorder) GetGeolocationDetails(ctx, addressFamily interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationDetails", reflect.TypeOf((*MockChainProviderContract)(nil).GetGeolocationDetails), ctx, addressFamily)
+}
@@ -0,0 +1,133 @@
+package chain
+
+import (
+	"sync"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/state"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// pacingThreshold is the share of the quota after which the remaining requests are spread evenly over the rest
+// of the period, stretching the effective schedule instead of running out before the period ends
+const pacingThreshold = 0.8
+
+var quotaRemainingGauge = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "edge_core_geolocation_provider_quota_remaining",
+		Help: "Number of requests left in the current quota period of the geolocation provider",
+	},
+	[]string{"provider"})
+
+// requestBudget keeps track of the requests sent to a provider, so the provider is not called while it asked to
+// wait through Retry-After, or when calling it would exceed its quota
+type requestBudget struct {
+	lock         sync.Mutex
+	name         string
+	quota        configuration.ProviderQuota
+	windowStart  time.Time
+	used         int
+	lastRequest  time.Time
+	blockedUntil time.Time
+}
+
+// newRequestBudget creates the request budget of a provider, continuing from the given saved usage if any
+func newRequestBudget(name string, quota configuration.ProviderQuota, usage *state.QuotaUsage) *requestBudget {
+	budget := &requestBudget{
+		name:  name,
+		quota: quota,
+	}
+
+	if usage != nil {
+		budget.windowStart = usage.WindowStart
+		budget.used = usage.Used
+		budget.lastRequest = usage.LastRequest
+		budget.blockedUntil = usage.BlockedUntil
+	}
+
+	if quota.Requests > 0 {
+		budget.startWindow(time.Now())
+		quotaRemainingGauge.WithLabelValues(name).Set(float64(quota.Requests - budget.used))
+	}
+
+	return budget
+}
+
+// allow determines whether the provider can be called now without exceeding its budget. The request is only
+// taken from the budget through record once it reached the provider.
+// Returns true if the provider can be called, otherwise false and the time the provider can be called again
+func (budget *requestBudget) allow(now time.Time) (bool, time.Time) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if now.Before(budget.blockedUntil) {
+		return false, budget.blockedUntil
+	}
+
+	if budget.quota.Requests > 0 {
+		budget.startWindow(now)
+
+		windowEnd := budget.windowStart.Add(budget.quota.Period)
+		remaining := budget.quota.Requests - budget.used
+
+		if remaining <= 0 {
+			return false, windowEnd
+		}
+
+		if float64(budget.used) >= pacingThreshold*float64(budget.quota.Requests) {
+			minInterval := windowEnd.Sub(now) / time.Duration(remaining)
+			if next := budget.lastRequest.Add(minInterval); now.Before(next) {
+				return false, next
+			}
+		}
+	}
+
+	return true, time.Time{}
+}
+
+// record takes a request sent to the provider at the given time from the budget
+func (budget *requestBudget) record(now time.Time) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	budget.lastRequest = now
+
+	if budget.quota.Requests > 0 {
+		budget.startWindow(now)
+		budget.used++
+		quotaRemainingGauge.WithLabelValues(budget.name).Set(float64(budget.quota.Requests - budget.used))
+	}
+}
+
+// getUsage returns the usage of the budget to be saved
+func (budget *requestBudget) getUsage() state.QuotaUsage {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	return state.QuotaUsage{
+		WindowStart:  budget.windowStart,
+		Used:         budget.used,
+		LastRequest:  budget.lastRequest,
+		BlockedUntil: budget.blockedUntil,
+	}
+}
+
+// startWindow starts a new quota period if the current one ended
+func (budget *requestBudget) startWindow(now time.Time) {
+	if budget.windowStart.IsZero() || now.Sub(budget.windowStart) >= budget.quota.Period {
+		budget.windowStart = now
+		budget.used = 0
+	}
+}
+
+// block stops the provider from being called until the given time, e.g. because it responded with Retry-After
+func (budget *requestBudget) block(until time.Time) {
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+
+	if until.After(budget.blockedUntil) {
+		budget.blockedUntil = until
+	}
+}
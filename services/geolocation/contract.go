@@ -0,0 +1,59 @@
+// Package geolocation implements different geolocation providers required by the edge-core
+package geolocation
+
+import (
+	"context"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+)
+
+// GeolocationDetails contains the provider-neutral public IP address and geolocation details of the node
+type GeolocationDetails struct {
+	// Ip is the node public IP address
+	Ip string `json:"ip"`
+
+	// PublicPort is the public port the NAT mapped the node to, or zero if the provider can not discover it
+	PublicPort int `json:"publicPort,omitempty"`
+
+	// NatType is the type of NAT in front of the node, or empty if the provider can not discover it
+	NatType string `json:"natType,omitempty"`
+
+	// Hostname is the reverse DNS hostname of the node public IP address
+	Hostname string `json:"hostname,omitempty"`
+
+	// City is the city the node public IP address is located in
+	City string `json:"city,omitempty"`
+
+	// Region is the region the node public IP address is located in
+	Region string `json:"region,omitempty"`
+
+	// Country is the two letter ISO 3166 country code the node public IP address is located in
+	Country string `json:"country,omitempty"`
+
+	// Loc is the comma separated latitude and longitude the node public IP address is located at
+	Loc string `json:"loc,omitempty"`
+
+	// Org is the autonomous system number and the name of the organization that owns the node public IP address
+	Org string `json:"org,omitempty"`
+
+	// Postal is the postal code the node public IP address is located in
+	Postal string `json:"postal,omitempty"`
+
+	// Timezone is the IANA timezone the node public IP address is located in
+	Timezone string `json:"timezone,omitempty"`
+
+	// Provider is the name of the provider(s) the details are resolved by
+	Provider string `json:"provider,omitempty"`
+
+	// Source is manual if the location is overridden through the annotations of the node, otherwise empty
+	Source string `json:"source,omitempty"`
+}
+
+// GeolocationProviderContract declares the methods to be implemented by the geolocation provider
+type GeolocationProviderContract interface {
+	// GetGeolocationDetails returns the node public IP address and geolocation details
+	// ctx: Mandatory. The reference to the context
+	// addressFamily: Mandatory. The address family to resolve the node public IP address for
+	// Returns the node public IP address and geolocation details or error if something goes wrong
+	GetGeolocationDetails(ctx context.Context, addressFamily configuration.AddressFamily) (*GeolocationDetails, error)
+}
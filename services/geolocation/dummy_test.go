@@ -0,0 +1 @@
+package geolocation_test
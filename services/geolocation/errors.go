@@ -0,0 +1,74 @@
+package geolocation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HttpStatusError is returned by the providers when the HTTP endpoint they call responds with a non-2xx status
+type HttpStatusError struct {
+	// Url is the URL of the endpoint
+	Url string
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// RetryAfter is how long the endpoint asked to wait before the next request, or zero if it did not say
+	RetryAfter time.Duration
+}
+
+// NewHttpStatusError creates a new HttpStatusError from the given response, parsing the Retry-After header
+// response: Mandatory. The non-2xx response
+// Returns the new error
+func NewHttpStatusError(response *http.Response) *HttpStatusError {
+	err := &HttpStatusError{StatusCode: response.StatusCode}
+
+	if response.Request != nil && response.Request.URL != nil {
+		err.Url = response.Request.URL.Redacted()
+	}
+
+	// Retry-After is either a number of seconds or an HTTP date
+	if value := strings.TrimSpace(response.Header.Get("Retry-After")); value != "" {
+		if seconds, parseErr := strconv.Atoi(value); parseErr == nil && seconds > 0 {
+			err.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, parseErr := http.ParseTime(value); parseErr == nil && time.Until(date) > 0 {
+			err.RetryAfter = time.Until(date)
+		}
+	}
+
+	return err
+}
+
+// Error returns the error message
+func (err *HttpStatusError) Error() string {
+	if err.RetryAfter > 0 {
+		return fmt.Sprintf("%s responded with %d, retry after %s", err.Url, err.StatusCode, err.RetryAfter)
+	}
+
+	return fmt.Sprintf("%s responded with %d", err.Url, err.StatusCode)
+}
+
+// Retryable determines whether the request can succeed if it is sent again, which is the case when the endpoint
+// is rate limiting or has a server side problem
+// Returns true if the request can be retried otherwise returns false
+func (err *HttpStatusError) Retryable() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= http.StatusInternalServerError
+}
+
+// ThrottledError is returned when a provider is not called because it asked to wait, or because calling it would
+// exceed its request budget
+type ThrottledError struct {
+	// Provider is the name of the throttled provider
+	Provider string
+
+	// Until is the time the provider can be called again
+	Until time.Time
+}
+
+// Error returns the error message
+func (err *ThrottledError) Error() string {
+	return fmt.Sprintf("%s is throttled until %s", err.Provider, err.Until.Format(time.RFC3339))
+}
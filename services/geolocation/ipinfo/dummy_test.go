@@ -0,0 +1 @@
+package ipinfo_test
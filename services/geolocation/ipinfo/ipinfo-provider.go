@@ -0,0 +1,175 @@
+// Package ipinfo implements the geolocation provider that uses Ipinfo website to resolve the node public IP and geolocation details
+package ipinfo
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/decentralized-cloud/edge-core/pkg/httpclient"
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+type ipinfoProvider struct {
+	logger            *zap.Logger
+	ipinfoUrl         string
+	ipinfoAccessToken string
+	publicIPResolver  publicip.PublicIPResolverContract
+}
+
+type ipinfoResponse struct {
+	Ip       string
+	Hostname string
+	City     string
+	Region   string
+	Country  string
+	Loc      string
+	Org      string
+	Postal   string
+	Timezone string
+}
+
+// NewIpinfoProvider creates new instance of the ipinfoProvider, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// publicIPResolver: Optional. Reference to the resolver that discovers the node public IP address. If provided,
+// Ipinfo is asked for the details of the discovered address, otherwise of the address the request comes from
+// Returns the new provider or error if something goes wrong
+func NewIpinfoProvider(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract,
+	publicIPResolver publicip.PublicIPResolverContract) (geolocation.GeolocationProviderContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	ipinfoUrl, err := configurationService.GetIpinfoUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	ipinfoAccessToken, err := configurationService.GetIpinfoAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipinfoProvider{
+		logger:            logger,
+		ipinfoUrl:         ipinfoUrl,
+		ipinfoAccessToken: ipinfoAccessToken,
+		publicIPResolver:  publicIPResolver,
+	}, nil
+}
+
+// GetGeolocationDetails returns the node public IP address and geolocation details
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to resolve the node public IP address for
+// Returns the node public IP address and geolocation details or error if something goes wrong
+func (provider *ipinfoProvider) GetGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	ipinfoUrl := provider.ipinfoUrl
+
+	var publicIPDetails *publicip.PublicIPDetails
+
+	if provider.publicIPResolver != nil {
+		var err error
+
+		publicIPDetails, err = provider.publicIPResolver.ResolvePublicIP(ctx, addressFamily)
+		if err != nil {
+			return nil, err
+		}
+
+		ipinfoUrl = strings.TrimSuffix(provider.ipinfoUrl, "/") + "/" + publicIPDetails.Ip
+	}
+
+	httpClient := httpclient.ForAddressFamily(addressFamily)
+	request, err := http.NewRequestWithContext(ctx, "GET", ipinfoUrl, nil)
+	if err != nil {
+		provider.logger.Error(
+			"Failed to create a new request to Ipinfo",
+			zap.String("ipinfoUrl", ipinfoUrl),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	if strings.Trim(provider.ipinfoAccessToken, " ") != "" {
+		request.Header.Set("Authorization", "Bearer "+provider.ipinfoAccessToken)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		provider.logger.Error("Failed to send request to Ipinfo", zap.String("ipinfoUrl", ipinfoUrl), zap.Error(err))
+
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	// Rate limited and failed responses are not JSON, and 429 and 5xx ones can tell how long to back off for
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		statusErr := geolocation.NewHttpStatusError(response)
+
+		provider.logger.Error(
+			"Ipinfo responded with an unsuccessful status",
+			zap.String("ipinfoUrl", ipinfoUrl),
+			zap.Int("statusCode", response.StatusCode),
+			zap.Duration("retryAfter", statusErr.RetryAfter))
+
+		return nil, statusErr
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		provider.logger.Error(
+			"Failed to read Ipinfo reponse body",
+			zap.String("ipinfoUrl", ipinfoUrl),
+			zap.String("response", string(body)),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	var ipinfoResponse ipinfoResponse
+
+	err = json.Unmarshal(body, &ipinfoResponse)
+	if err != nil {
+		provider.logger.Error(
+			"Can't deserialize Ipinfo response",
+			zap.String("ipinfoUrl", ipinfoUrl),
+			zap.String("response", string(body)),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	geolocationDetails := &geolocation.GeolocationDetails{
+		Ip:       ipinfoResponse.Ip,
+		Hostname: ipinfoResponse.Hostname,
+		City:     ipinfoResponse.City,
+		Region:   ipinfoResponse.Region,
+		Country:  ipinfoResponse.Country,
+		Loc:      ipinfoResponse.Loc,
+		Org:      ipinfoResponse.Org,
+		Postal:   ipinfoResponse.Postal,
+		Timezone: ipinfoResponse.Timezone,
+	}
+
+	if publicIPDetails != nil {
+		geolocationDetails.PublicPort = publicIPDetails.Port
+		geolocationDetails.NatType = publicIPDetails.NatType
+	}
+
+	return geolocationDetails, nil
+}
@@ -0,0 +1,88 @@
+package maxmind
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"go.uber.org/zap"
+)
+
+// database wraps a MaxMind mmdb reader and transparently reopens it whenever the file on disk changes,
+// so databases refreshed by geoipupdate or a mounted volume are picked up without restarting the pod
+type database struct {
+	logger  *zap.Logger
+	path    string
+	lock    sync.Mutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+func newDatabase(logger *zap.Logger, path string) *database {
+	return &database{
+		logger: logger,
+		path:   path,
+	}
+}
+
+// lookup finds the record of the given IP address and decodes it into the result
+func (db *database) lookup(ip net.IP, result interface{}) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.reloadIfChanged(); err != nil {
+		return err
+	}
+
+	return db.reader.Lookup(ip, result)
+}
+
+func (db *database) reloadIfChanged() error {
+	fileInfo, err := os.Stat(db.path)
+	if err != nil {
+		if db.reader != nil {
+			db.logger.Warn("Failed to check MaxMind database file, using the already loaded database", zap.String("path", db.path), zap.Error(err))
+
+			return nil
+		}
+
+		db.logger.Error("Failed to check MaxMind database file", zap.String("path", db.path), zap.Error(err))
+
+		return err
+	}
+
+	if db.reader != nil && fileInfo.ModTime().Equal(db.modTime) && fileInfo.Size() == db.size {
+		return nil
+	}
+
+	reader, err := maxminddb.Open(db.path)
+	if err != nil {
+		if db.reader != nil {
+			db.logger.Warn("Failed to reload MaxMind database, using the already loaded database", zap.String("path", db.path), zap.Error(err))
+
+			return nil
+		}
+
+		db.logger.Error("Failed to open MaxMind database", zap.String("path", db.path), zap.Error(err))
+
+		return err
+	}
+
+	if db.reader != nil {
+		_ = db.reader.Close()
+	}
+
+	db.reader = reader
+	db.modTime = fileInfo.ModTime()
+	db.size = fileInfo.Size()
+	db.logger.Info(
+		"Loaded MaxMind database",
+		zap.String("path", db.path),
+		zap.String("databaseType", reader.Metadata.DatabaseType),
+		zap.Time("modTime", db.modTime))
+
+	return nil
+}
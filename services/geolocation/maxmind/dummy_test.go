@@ -0,0 +1 @@
+package maxmind_test
@@ -0,0 +1,168 @@
+// Package maxmind implements the geolocation provider that uses local MaxMind GeoLite2/GeoIP2 databases to resolve
+// the node geolocation details
+package maxmind
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+type maxmindProvider struct {
+	logger           *zap.Logger
+	publicIPResolver publicip.PublicIPResolverContract
+	cityDatabase     *database
+	asnDatabase      *database
+}
+
+type names struct {
+	English string `maxminddb:"en"`
+}
+
+// record is the subset of the GeoLite2/GeoIP2 City and ASN database records required by the provider. The same
+// struct is used to decode both databases as the field names do not overlap.
+type record struct {
+	City struct {
+		Names names `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Subdivisions []struct {
+		Names names `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	Country struct {
+		IsoCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+		TimeZone  string   `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+	Postal struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"postal"`
+	AutonomousSystemNumber       uint   `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// NewMaxMindProvider creates new instance of the maxmindProvider, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// publicIPResolver: Mandatory. Reference to the resolver that discovers the node public IP address
+// Returns the new provider or error if something goes wrong
+func NewMaxMindProvider(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract,
+	publicIPResolver publicip.PublicIPResolverContract) (geolocation.GeolocationProviderContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	if publicIPResolver == nil {
+		return nil, commonErrors.NewArgumentNilError("publicIPResolver", "publicIPResolver is required")
+	}
+
+	cityDatabasePath, err := configurationService.GetMaxMindCityDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	asnDatabasePath, err := configurationService.GetMaxMindAsnDatabasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &maxmindProvider{
+		logger:           logger,
+		publicIPResolver: publicIPResolver,
+		cityDatabase:     newDatabase(logger, cityDatabasePath),
+	}
+
+	if strings.Trim(asnDatabasePath, " ") != "" {
+		provider.asnDatabase = newDatabase(logger, asnDatabasePath)
+	}
+
+	return provider, nil
+}
+
+// GetGeolocationDetails returns the node public IP address and geolocation details
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to resolve the node public IP address for
+// Returns the node public IP address and geolocation details or error if something goes wrong
+func (provider *maxmindProvider) GetGeolocationDetails(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	publicIPDetails, err := provider.publicIPResolver.ResolvePublicIP(ctx, addressFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(publicIPDetails.Ip)
+	if ip == nil {
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s is not a valid IP address", publicIPDetails.Ip))
+	}
+
+	var record record
+
+	if err = provider.cityDatabase.lookup(ip, &record); err != nil {
+		provider.logger.Error("Failed to lookup IP address in MaxMind city database", zap.String("ip", publicIPDetails.Ip), zap.Error(err))
+
+		return nil, err
+	}
+
+	if provider.asnDatabase != nil {
+		if err = provider.asnDatabase.lookup(ip, &record); err != nil {
+			provider.logger.Error("Failed to lookup IP address in MaxMind ASN database", zap.String("ip", publicIPDetails.Ip), zap.Error(err))
+
+			return nil, err
+		}
+	}
+
+	geolocationDetails := &geolocation.GeolocationDetails{
+		Ip:         publicIPDetails.Ip,
+		PublicPort: publicIPDetails.Port,
+		NatType:    publicIPDetails.NatType,
+		Hostname:   provider.lookupHostname(ctx, publicIPDetails.Ip),
+		City:       record.City.Names.English,
+		Country:    record.Country.IsoCode,
+		Postal:     record.Postal.Code,
+		Timezone:   record.Location.TimeZone,
+	}
+
+	if len(record.Subdivisions) > 0 {
+		geolocationDetails.Region = record.Subdivisions[0].Names.English
+	}
+
+	if record.Location.Latitude != nil && record.Location.Longitude != nil {
+		geolocationDetails.Loc = strconv.FormatFloat(*record.Location.Latitude, 'f', 4, 64) + "," +
+			strconv.FormatFloat(*record.Location.Longitude, 'f', 4, 64)
+	}
+
+	if record.AutonomousSystemNumber != 0 {
+		geolocationDetails.Org = strings.TrimSpace(fmt.Sprintf("AS%d %s", record.AutonomousSystemNumber, record.AutonomousSystemOrganization))
+	}
+
+	return geolocationDetails, nil
+}
+
+// lookupHostname returns the reverse DNS hostname of the given IP address, or an empty string if there is none
+func (provider *maxmindProvider) lookupHostname(ctx context.Context, ip string) string {
+	hostnames, err := net.DefaultResolver.LookupAddr(ctx, ip)
+	if err != nil || len(hostnames) == 0 {
+		provider.logger.Debug("No reverse DNS hostname found for the public IP address", zap.String("ip", ip), zap.Error(err))
+
+		return ""
+	}
+
+	return strings.TrimSuffix(hostnames[0], ".")
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/geolocation/contract.go
+
+// Package mock_geolocation is a generated GoMock package.
+package mock_geolocation
+
+import (
+	context "context"
+	reflect "reflect"
+
+	configuration "github.com/decentralized-cloud/edge-core/services/configuration"
+	geolocation "github.com/decentralized-cloud/edge-core/services/geolocation"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGeolocationProviderContract is a mock of GeolocationProviderContract interface.
+type MockGeolocationProviderContract struct {
+	ctrl     *gomock.Controller
+	recorder *MockGeolocationProviderContractMockRecorder
+}
+
+// MockGeolocationProviderContractMockRecorder is the mock recorder for MockGeolocationProviderContract.
+type MockGeolocationProviderContractMockRecorder struct {
+	mock *MockGeolocationProviderContract
+}
+
+// NewMockGeolocationProviderContract creates a new mock instance.
+func NewMockGeolocationProviderContract(ctrl *gomock.Controller) *MockGeolocationProviderContract {
+	mock := &MockGeolocationProviderContract{ctrl: ctrl}
+	mock.recorder = &MockGeolocationProviderContractMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGeolocationProviderContract) EXPECT() *MockGeolocationProviderContractMockRecorder {
+	return m.recorder
+}
+
+// GetGeolocationDetails mocks base method.
+func (m *MockGeolocationProviderContract) GetGeolocationDetails(ctx context.Context, addressFamily configuration.AddressFamily) (*geolocation.GeolocationDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGeolocationDetails", ctx, addressFamily)
+	ret0, _ := ret[0].(*geolocation.GeolocationDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGeolocationDetails indicates an expected call of GetGeolocationDetails.
+func (mr *MockGeolocationProviderContractMockRecorder) GetGeolocationDetails(ctx, addressFamily interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGeolocationDetails", reflect.TypeOf((*MockGeolocationProviderContract)(nil).GetGeolocationDetails), ctx, addressFamily)
+}
@@ -0,0 +1,40 @@
+// Package publicip implements different public IP resolvers required by the edge-core
+package publicip
+
+import (
+	"context"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+)
+
+const (
+	// NatTypeUnknown determines that the resolver could not determine the NAT type in front of the node
+	NatTypeUnknown = "unknown"
+	// NatTypeNone determines that the node public IP address is assigned to one of the node network interfaces
+	NatTypeNone = "none"
+	// NatTypeCone determines that the NAT maps the node to the same public address and port regardless of the destination
+	NatTypeCone = "cone"
+	// NatTypeSymmetric determines that the NAT maps the node to a different public address or port per destination
+	NatTypeSymmetric = "symmetric"
+)
+
+// PublicIPDetails contains the node public IP address discovered by the public IP resolver
+type PublicIPDetails struct {
+	// Ip is the node public IP address
+	Ip string
+
+	// Port is the public port the NAT mapped the resolver request to, or zero if the resolver can not discover it
+	Port int
+
+	// NatType is the type of NAT in front of the node, or empty if the resolver can not discover it
+	NatType string
+}
+
+// PublicIPResolverContract declares the methods to be implemented by the public IP resolver
+type PublicIPResolverContract interface {
+	// ResolvePublicIP discovers the node public IP address
+	// ctx: Mandatory. The reference to the context
+	// addressFamily: Mandatory. The address family to discover the node public IP address for
+	// Returns the node public IP address or error if something goes wrong
+	ResolvePublicIP(ctx context.Context, addressFamily configuration.AddressFamily) (*PublicIPDetails, error)
+}
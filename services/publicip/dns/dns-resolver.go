@@ -0,0 +1,144 @@
+// Package dns implements the public IP resolver that uses myip-style DNS queries, such as the myip.opendns.com A
+// record or the o-o.myaddr.l.google.com TXT record, to discover the node public IP address
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+type dnsResolver struct {
+	logger  *zap.Logger
+	queries []configuration.DnsPublicIPQuery
+}
+
+// NewDnsResolver creates new instance of the dnsResolver, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// Returns the new resolver or error if something goes wrong
+func NewDnsResolver(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract) (publicip.PublicIPResolverContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	queries, err := configurationService.GetDnsPublicIPQueries()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(queries) == 0 {
+		return nil, commonErrors.NewArgumentError("queries", "at least one DNS query is required")
+	}
+
+	return &dnsResolver{
+		logger:  logger,
+		queries: queries,
+	}, nil
+}
+
+// ResolvePublicIP discovers the node public IP address
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to discover the node public IP address for
+// Returns the node public IP address or error if something goes wrong
+func (resolver *dnsResolver) ResolvePublicIP(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*publicip.PublicIPDetails, error) {
+	for _, query := range resolver.queries {
+		// A records can only return IPv4 and AAAA records can only return IPv6 addresses
+		if (query.RecordType == "A" && addressFamily == configuration.IPv6) ||
+			(query.RecordType == "AAAA" && addressFamily == configuration.IPv4) {
+			continue
+		}
+
+		ip, err := resolver.runQuery(ctx, query, addressFamily)
+		if err != nil {
+			resolver.logger.Warn(
+				"DNS public IP query failed",
+				zap.String("recordType", query.RecordType),
+				zap.String("name", query.Name),
+				zap.String("server", query.Server),
+				zap.Error(err))
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			continue
+		}
+
+		return &publicip.PublicIPDetails{Ip: ip.String()}, nil
+	}
+
+	return nil, commonErrors.NewUnknownError("none of the DNS queries returned the public IP address")
+}
+
+func (resolver *dnsResolver) runQuery(
+	ctx context.Context,
+	query configuration.DnsPublicIPQuery,
+	addressFamily configuration.AddressFamily) (net.IP, error) {
+	// The query must go to the configured server rather than the node resolver, as only the authoritative
+	// server of the myip-style names knows the address the query came from. The address family of the
+	// connection to the server determines the address family of the returned address.
+	netResolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialer := net.Dialer{}
+
+			return dialer.DialContext(ctx, addressFamily.Network(network), query.Server)
+		},
+	}
+
+	// A trailing dot makes the name absolute so the search domains of the node are not appended to it
+	name := query.Name
+	if !strings.HasSuffix(name, ".") {
+		name = name + "."
+	}
+
+	switch query.RecordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if query.RecordType == "AAAA" {
+			network = "ip6"
+		}
+
+		ips, err := netResolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(ips) == 0 {
+			return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s returned no %s record", query.Server, query.RecordType))
+		}
+
+		return ips[0], nil
+	case "TXT":
+		records, err := netResolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Some servers return additional TXT records, e.g. the EDNS client subnet, so take the first one that is an IP
+		for _, record := range records {
+			if ip := net.ParseIP(strings.Trim(record, "\" ")); ip != nil {
+				return ip, nil
+			}
+		}
+
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s returned no TXT record containing an IP address", query.Server))
+	default:
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("DNS record type %s is not supported", query.RecordType))
+	}
+}
@@ -0,0 +1 @@
+package dns_test
@@ -0,0 +1 @@
+package publicip_test
@@ -0,0 +1 @@
+package http_test
@@ -0,0 +1,119 @@
+// Package http implements the public IP resolver that uses a plain text HTTP endpoint to discover the node public IP
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/decentralized-cloud/edge-core/pkg/httpclient"
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+// maxResponseSize is the maximum number of bytes read from the resolver response body. The endpoint
+// is expected to return the bare IP address, so anything bigger than this is not a valid response.
+const maxResponseSize = 1024
+
+type httpResolver struct {
+	logger      *zap.Logger
+	resolverUrl string
+}
+
+// NewHttpResolver creates new instance of the httpResolver, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// Returns the new resolver or error if something goes wrong
+func NewHttpResolver(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract) (publicip.PublicIPResolverContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	resolverUrl, err := configurationService.GetPublicIPResolverUrl()
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpResolver{
+		logger:      logger,
+		resolverUrl: resolverUrl,
+	}, nil
+}
+
+// ResolvePublicIP discovers the node public IP address
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to discover the node public IP address for
+// Returns the node public IP address or error if something goes wrong
+func (resolver *httpResolver) ResolvePublicIP(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*publicip.PublicIPDetails, error) {
+	httpClient := httpclient.ForAddressFamily(addressFamily)
+	request, err := http.NewRequestWithContext(ctx, "GET", resolver.resolverUrl, nil)
+	if err != nil {
+		resolver.logger.Error(
+			"Failed to create a new request to the public IP resolver",
+			zap.String("resolverUrl", resolver.resolverUrl),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		resolver.logger.Error(
+			"Failed to send request to the public IP resolver",
+			zap.String("resolverUrl", resolver.resolverUrl),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		statusErr := geolocation.NewHttpStatusError(response)
+
+		resolver.logger.Error(
+			"The public IP resolver responded with an unsuccessful status",
+			zap.String("resolverUrl", resolver.resolverUrl),
+			zap.Int("statusCode", response.StatusCode),
+			zap.Duration("retryAfter", statusErr.RetryAfter))
+
+		return nil, statusErr
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		resolver.logger.Error(
+			"Failed to read the public IP resolver response body",
+			zap.String("resolverUrl", resolver.resolverUrl),
+			zap.Error(err))
+
+		return nil, err
+	}
+
+	value := strings.TrimSpace(string(body))
+	if net.ParseIP(value) == nil {
+		resolver.logger.Error(
+			"The public IP resolver did not return a valid IP address",
+			zap.String("resolverUrl", resolver.resolverUrl),
+			zap.Int("statusCode", response.StatusCode),
+			zap.String("response", value))
+
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s did not return a valid IP address", resolver.resolverUrl))
+	}
+
+	return &publicip.PublicIPDetails{Ip: value}, nil
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/publicip/contract.go
+
+// Package mock_publicip is a generated GoMock package.
+package mock_publicip
+
+import (
+	context "context"
+	reflect "reflect"
+
+	configuration "github.com/decentralized-cloud/edge-core/services/configuration"
+	publicip "github.com/decentralized-cloud/edge-core/services/publicip"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPublicIPResolverContract is a mock of PublicIPResolverContract interface.
+type MockPublicIPResolverContract struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublicIPResolverContractMockRecorder
+}
+
+// MockPublicIPResolverContractMockRecorder is the mock recorder for MockPublicIPResolverContract.
+type MockPublicIPResolverContractMockRecorder struct {
+	mock *MockPublicIPResolverContract
+}
+
+// NewMockPublicIPResolverContract creates a new mock instance.
+func NewMockPublicIPResolverContract(ctrl *gomock.Controller) *MockPublicIPResolverContract {
+	mock := &MockPublicIPResolverContract{ctrl: ctrl}
+	mock.recorder = &MockPublicIPResolverContractMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublicIPResolverContract) EXPECT() *MockPublicIPResolverContractMockRecorder {
+	return m.recorder
+}
+
+// ResolvePublicIP mocks base method.
+func (m *MockPublicIPResolverContract) ResolvePublicIP(ctx context.Context, addressFamily configuration.AddressFamily) (*publicip.PublicIPDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePublicIP", ctx, addressFamily)
+	ret0, _ := ret[0].(*publicip.PublicIPDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolvePublicIP indicates an expected call of ResolvePublicIP.
+func (mr *MockPublicIPResolverContractMockRecorder) ResolvePublicIP(ctx, addressFamily interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePublicIP", reflect.TypeOf((*MockPublicIPResolverContract)(nil).ResolvePublicIP), ctx, addressFamily)
+}
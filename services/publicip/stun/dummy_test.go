@@ -0,0 +1 @@
+package stun_test
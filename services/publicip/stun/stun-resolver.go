@@ -0,0 +1,265 @@
+// Package stun implements the public IP resolver that uses STUN binding requests (RFC 5389) to discover the node
+// public IP address, the mapped port and the type of NAT in front of the node
+package stun
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"github.com/pion/stun"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxAttempts is the number of times a binding request is sent to a server before giving up on it, as UDP
+	// packets can be lost on the way
+	maxAttempts = 3
+
+	// attemptTimeout is the time to wait for a binding response before retransmitting the request
+	attemptTimeout = time.Second
+
+	// maxMessageSize is the size of the buffer the binding responses are read into
+	maxMessageSize = 1500
+)
+
+type stunResolver struct {
+	logger  *zap.Logger
+	servers []string
+}
+
+type mappedAddress struct {
+	ip   net.IP
+	port int
+}
+
+// NewStunResolver creates new instance of the stunResolver, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// Returns the new resolver or error if something goes wrong
+func NewStunResolver(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract) (publicip.PublicIPResolverContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	servers, err := configurationService.GetStunServers()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(servers) == 0 {
+		return nil, commonErrors.NewArgumentError("servers", "at least one STUN server is required")
+	}
+
+	return &stunResolver{
+		logger:  logger,
+		servers: servers,
+	}, nil
+}
+
+// ResolvePublicIP discovers the node public IP address
+// ctx: Mandatory. The reference to the context
+// addressFamily: Mandatory. The address family to discover the node public IP address for
+// Returns the node public IP address or error if something goes wrong
+func (resolver *stunResolver) ResolvePublicIP(
+	ctx context.Context,
+	addressFamily configuration.AddressFamily) (*publicip.PublicIPDetails, error) {
+	// All binding requests are sent from the same local socket, so the mapped addresses returned by
+	// different servers can be compared to find out how the NAT maps the node
+	conn, err := net.ListenPacket(addressFamily.Network("udp"), ":0")
+	if err != nil {
+		resolver.logger.Error("Failed to open UDP socket for STUN binding requests", zap.Error(err))
+
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	mappedAddresses := []mappedAddress{}
+
+	for _, server := range resolver.servers {
+		address, err := resolver.bind(ctx, conn, server, addressFamily)
+		if err != nil {
+			resolver.logger.Warn("STUN binding request failed", zap.String("server", server), zap.Error(err))
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			continue
+		}
+
+		mappedAddresses = append(mappedAddresses, *address)
+
+		if len(mappedAddresses) == 2 {
+			break
+		}
+	}
+
+	if len(mappedAddresses) == 0 {
+		return nil, commonErrors.NewUnknownError("none of the STUN servers returned the mapped address")
+	}
+
+	return &publicip.PublicIPDetails{
+		Ip:      mappedAddresses[0].ip.String(),
+		Port:    mappedAddresses[0].port,
+		NatType: resolver.getNatType(conn.LocalAddr(), mappedAddresses),
+	}, nil
+}
+
+// bind sends a binding request to the given server and returns the mapped address from the server response
+func (resolver *stunResolver) bind(
+	ctx context.Context,
+	conn net.PacketConn,
+	server string,
+	addressFamily configuration.AddressFamily) (*mappedAddress, error) {
+	serverAddress, err := resolveServerAddress(ctx, server, addressFamily)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := stun.Build(stun.TransactionID, stun.BindingRequest, stun.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, maxMessageSize)
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if _, err = conn.WriteTo(request.Raw, serverAddress); err != nil {
+			return nil, err
+		}
+
+		deadline := time.Now().Add(attemptTimeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+
+		if err = conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+
+		for {
+			count, from, err := conn.ReadFrom(buffer)
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					break
+				}
+
+				return nil, err
+			}
+
+			response := &stun.Message{}
+			if err = stun.Decode(buffer[:count], response); err != nil || response.TransactionID != request.TransactionID {
+				resolver.logger.Debug("Ignoring unexpected packet on the STUN socket", zap.String("from", from.String()))
+
+				continue
+			}
+
+			if response.Type != stun.BindingSuccess {
+				return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s responded with %s", server, response.Type))
+			}
+
+			var xorAddress stun.XORMappedAddress
+			if err = xorAddress.GetFrom(response); err == nil {
+				return &mappedAddress{ip: xorAddress.IP, port: xorAddress.Port}, nil
+			}
+
+			// Fall back to MAPPED-ADDRESS for RFC 3489 servers that do not return XOR-MAPPED-ADDRESS
+			var address stun.MappedAddress
+			if err = address.GetFrom(response); err != nil {
+				return nil, commonErrors.NewUnknownErrorWithError(fmt.Sprintf("%s did not return the mapped address", server), err)
+			}
+
+			return &mappedAddress{ip: address.IP, port: address.Port}, nil
+		}
+	}
+
+	return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s did not respond to the binding request", server))
+}
+
+// getNatType compares the local address with the mapped addresses returned by different servers to figure out
+// the type of NAT in front of the node
+func (resolver *stunResolver) getNatType(localAddress net.Addr, mappedAddresses []mappedAddress) string {
+	if isLocalAddress(mappedAddresses[0].ip) {
+		return publicip.NatTypeNone
+	}
+
+	if len(mappedAddresses) < 2 {
+		return publicip.NatTypeUnknown
+	}
+
+	if mappedAddresses[0].ip.Equal(mappedAddresses[1].ip) && mappedAddresses[0].port == mappedAddresses[1].port {
+		return publicip.NatTypeCone
+	}
+
+	resolver.logger.Debug(
+		"STUN servers returned different mapped addresses",
+		zap.String("localAddress", localAddress.String()),
+		zap.String("firstMappedIp", mappedAddresses[0].ip.String()),
+		zap.Int("firstMappedPort", mappedAddresses[0].port),
+		zap.String("secondMappedIp", mappedAddresses[1].ip.String()),
+		zap.Int("secondMappedPort", mappedAddresses[1].port))
+
+	return publicip.NatTypeSymmetric
+}
+
+func resolveServerAddress(ctx context.Context, server string, addressFamily configuration.AddressFamily) (*net.UDPAddr, error) {
+	host, portStr, err := net.SplitHostPort(server)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := net.DefaultResolver.LookupPort(ctx, "udp", portStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if !addressFamily.Matches(ip) {
+			return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s is not an %s address", host, addressFamily))
+		}
+
+		return &net.UDPAddr{IP: ip, Port: port}, nil
+	}
+
+	addresses, err := net.DefaultResolver.LookupIP(ctx, addressFamily.Network("ip"), host)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) == 0 {
+		return nil, commonErrors.NewUnknownError(fmt.Sprintf("%s does not resolve to any %s address", host, addressFamily))
+	}
+
+	return &net.UDPAddr{IP: addresses[0], Port: port}, nil
+}
+
+func isLocalAddress(ip net.IP) bool {
+	interfaceAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+
+	for _, interfaceAddress := range interfaceAddresses {
+		if ipNet, ok := interfaceAddress.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
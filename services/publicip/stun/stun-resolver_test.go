@@ -0,0 +1,135 @@
+package stun_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	mockConfiguration "github.com/decentralized-cloud/edge-core/services/configuration/mock"
+	"github.com/decentralized-cloud/edge-core/services/publicip"
+	stunResolver "github.com/decentralized-cloud/edge-core/services/publicip/stun"
+	"github.com/golang/mock/gomock"
+	"github.com/pion/stun"
+	"go.uber.org/zap"
+)
+
+// startStunServer starts an in-process STUN server on the loopback interface that answers binding requests with
+// the given mapped address, or with the address the request came from if mapped is nil
+// Returns the address of the server in host:port format
+func startStunServer(t *testing.T, mapped *net.UDPAddr) string {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start the STUN server: %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buffer := make([]byte, 1500)
+
+		for {
+			count, from, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+
+			request := &stun.Message{}
+			if err = stun.Decode(buffer[:count], request); err != nil || request.Type != stun.BindingRequest {
+				continue
+			}
+
+			address := mapped
+			if address == nil {
+				address = from.(*net.UDPAddr)
+			}
+
+			response, err := stun.Build(
+				stun.NewTransactionIDSetter(request.TransactionID),
+				stun.BindingSuccess,
+				&stun.XORMappedAddress{IP: address.IP, Port: address.Port},
+				stun.Fingerprint)
+			if err != nil {
+				continue
+			}
+
+			_, _ = conn.WriteTo(response.Raw, from)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func resolve(t *testing.T, servers ...string) *publicip.PublicIPDetails {
+	ctrl := gomock.NewController(t)
+	configurationService := mockConfiguration.NewMockConfigurationContract(ctrl)
+	configurationService.EXPECT().GetStunServers().Return(servers, nil)
+
+	resolver, err := stunResolver.NewStunResolver(zap.NewNop(), configurationService)
+	if err != nil {
+		t.Fatalf("failed to create the STUN resolver: %v", err)
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFunc()
+
+	details, err := resolver.ResolvePublicIP(ctx, configuration.IPv4)
+	if err != nil {
+		t.Fatalf("failed to resolve the public IP address: %v", err)
+	}
+
+	return details
+}
+
+func TestResolvePublicIP_MappedAddressIsLocal_ReturnsNoNat(t *testing.T) {
+	details := resolve(t, startStunServer(t, nil))
+
+	// The loopback stand-in sees the request coming from a local address, as if there was no NAT on the way
+	if details.Ip != "127.0.0.1" || details.Port == 0 || details.NatType != publicip.NatTypeNone {
+		t.Errorf("expected 127.0.0.1 with a mapped port and no NAT, got %+v", details)
+	}
+}
+
+func TestResolvePublicIP_ServersReturnSameMappedAddress_ReturnsConeNat(t *testing.T) {
+	mapped := &net.UDPAddr{IP: net.ParseIP("203.0.113.10"), Port: 40000}
+	details := resolve(t, startStunServer(t, mapped), startStunServer(t, mapped))
+
+	if details.Ip != "203.0.113.10" || details.Port != 40000 || details.NatType != publicip.NatTypeCone {
+		t.Errorf("expected 203.0.113.10:40000 behind a cone NAT, got %+v", details)
+	}
+}
+
+func TestResolvePublicIP_ServersReturnDifferentMappedPorts_ReturnsSymmetricNat(t *testing.T) {
+	details := resolve(
+		t,
+		startStunServer(t, &net.UDPAddr{IP: net.ParseIP("203.0.113.10"), Port: 40000}),
+		startStunServer(t, &net.UDPAddr{IP: net.ParseIP("203.0.113.10"), Port: 40001}))
+
+	if details.Ip != "203.0.113.10" || details.Port != 40000 || details.NatType != publicip.NatTypeSymmetric {
+		t.Errorf("expected 203.0.113.10:40000 behind a symmetric NAT, got %+v", details)
+	}
+}
+
+func TestResolvePublicIP_SingleServer_ReturnsUnknownNat(t *testing.T) {
+	details := resolve(t, startStunServer(t, &net.UDPAddr{IP: net.ParseIP("203.0.113.10"), Port: 40000}))
+
+	if details.Ip != "203.0.113.10" || details.NatType != publicip.NatTypeUnknown {
+		t.Errorf("expected 203.0.113.10 behind an unknown NAT, got %+v", details)
+	}
+}
+
+func TestResolvePublicIP_ServerDoesNotRespond_FallsBackToNextServer(t *testing.T) {
+	silent, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open the silent socket: %v", err)
+	}
+
+	defer silent.Close()
+
+	details := resolve(t, silent.LocalAddr().String(), startStunServer(t, &net.UDPAddr{IP: net.ParseIP("203.0.113.10"), Port: 40000}))
+
+	if details.Ip != "203.0.113.10" || details.Port != 40000 {
+		t.Errorf("expected 203.0.113.10:40000 from the second server, got %+v", details)
+	}
+}
@@ -0,0 +1,61 @@
+// Package state implements different stores that keep the last known public IP address and geolocation details
+// of the node across edge-core restarts
+package state
+
+import (
+	"time"
+
+	"github.com/decentralized-cloud/edge-core/services/geolocation"
+)
+
+// State contains the last successfully resolved public IP address and geolocation details of the node
+type State struct {
+	// UpdatedTime is the time the details were resolved
+	UpdatedTime time.Time `json:"updatedTime"`
+
+	// Geolocation is the primary public IP address and geolocation details of the node
+	Geolocation *geolocation.GeolocationDetails `json:"geolocation"`
+
+	// IPv4 is the IPv4 public IP address and geolocation details of the node, if it has one
+	IPv4 *geolocation.GeolocationDetails `json:"ipv4,omitempty"`
+
+	// IPv6 is the IPv6 public IP address and geolocation details of the node, if it has one
+	IPv6 *geolocation.GeolocationDetails `json:"ipv6,omitempty"`
+
+	// Quotas is the usage of the request quotas of the geolocation providers, keyed by the provider name, so a
+	// restart does not reset the number of requests sent in the current period
+	Quotas map[string]QuotaUsage `json:"quotas,omitempty"`
+}
+
+// QuotaUsage contains the requests sent to a geolocation provider in the current period of its quota
+type QuotaUsage struct {
+	// WindowStart is the time the current period started
+	WindowStart time.Time `json:"windowStart"`
+
+	// Used is the number of requests sent in the current period
+	Used int `json:"used"`
+
+	// LastRequest is the time the last request was sent
+	LastRequest time.Time `json:"lastRequest"`
+
+	// BlockedUntil is the time the provider asked not to be called before, e.g. through Retry-After
+	BlockedUntil time.Time `json:"blockedUntil"`
+}
+
+// StateStoreContract declares the methods to be implemented by the state store
+type StateStoreContract interface {
+	// Load returns the last saved state
+	// Returns the last saved state, or nil if nothing is saved yet, or error if something goes wrong
+	Load() (*State, error)
+
+	// Save saves the given state, replacing the previously saved one
+	// state: Mandatory. The state to save
+	// Returns error if something goes wrong
+	Save(state *State) error
+
+	// Update changes the saved state through the given function, so the services that keep different parts of
+	// the state do not overwrite the parts of each other
+	// update: Mandatory. The function that changes the state, it is given an empty state if nothing is saved yet
+	// Returns error if something goes wrong
+	Update(update func(state *State)) error
+}
@@ -0,0 +1 @@
+package state_test
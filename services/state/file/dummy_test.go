@@ -0,0 +1 @@
+package file_test
@@ -0,0 +1,208 @@
+// Package file implements the state store that keeps the state in a JSON file, e.g. on a hostPath volume so the
+// state survives the edge-core pod being recreated
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/decentralized-cloud/edge-core/services/configuration"
+	"github.com/decentralized-cloud/edge-core/services/state"
+	commonErrors "github.com/micro-business/go-core/system/errors"
+	"go.uber.org/zap"
+)
+
+type fileStore struct {
+	logger   *zap.Logger
+	filePath string
+	lock     sync.Mutex
+	loaded   bool
+	state    *state.State
+}
+
+// NewFileStore creates new instance of the fileStore, setting up all dependencies and returns the instance
+// logger: Mandatory. Reference to the logger service
+// configurationService: Mandatory. Reference to the service that provides required configurations
+// Returns the new store or error if something goes wrong
+func NewFileStore(
+	logger *zap.Logger,
+	configurationService configuration.ConfigurationContract) (state.StateStoreContract, error) {
+	if logger == nil {
+		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
+	}
+
+	if configurationService == nil {
+		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
+	}
+
+	filePath, err := configurationService.GetGeolocationStateFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileStore{
+		logger:   logger,
+		filePath: filePath,
+	}, nil
+}
+
+// Load returns the last saved state
+// Returns the last saved state, or nil if nothing is saved yet, or error if something goes wrong
+func (store *fileStore) Load() (*state.State, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.load()
+}
+
+// Save saves the given state, replacing the previously saved one
+// state: Mandatory. The state to save
+// Returns error if something goes wrong
+func (store *fileStore) Save(newState *state.State) error {
+	if newState == nil {
+		return commonErrors.NewArgumentNilError("state", "state is required")
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.save(newState)
+}
+
+// Update changes the saved state through the given function, so the services that keep different parts of
+// the state do not overwrite the parts of each other
+// update: Mandatory. The function that changes the state, it is given an empty state if nothing is saved yet
+// Returns error if something goes wrong
+func (store *fileStore) Update(update func(state *state.State)) error {
+	if update == nil {
+		return commonErrors.NewArgumentNilError("update", "update is required")
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	// A state file that can not be parsed would otherwise stop anything from being saved until it is deleted by
+	// hand, so it is replaced with the updated empty state instead
+	currentState, err := store.load()
+	if isCorrupt(err) {
+		store.logger.Warn(
+			"Replacing the state file that can not be parsed",
+			zap.String("filePath", store.filePath),
+			zap.Error(err))
+
+		currentState, err = nil, nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The loaded state is shared with the callers of Load, so it is copied instead of changed in place
+	newState := &state.State{}
+	if currentState != nil {
+		*newState = *currentState
+	}
+
+	newState.Quotas = map[string]state.QuotaUsage{}
+	if currentState != nil {
+		for name, usage := range currentState.Quotas {
+			newState.Quotas[name] = usage
+		}
+	}
+
+	update(newState)
+
+	return store.save(newState)
+}
+
+func (store *fileStore) load() (*state.State, error) {
+	// The file is only read once, as this instance is the only one writing to it
+	if store.loaded {
+		return store.state, nil
+	}
+
+	content, err := os.ReadFile(store.filePath)
+	if os.IsNotExist(err) {
+		store.loaded = true
+
+		return nil, nil
+	}
+
+	if err != nil {
+		store.logger.Error("Failed to read the state file", zap.String("filePath", store.filePath), zap.Error(err))
+
+		return nil, err
+	}
+
+	loadedState := &state.State{}
+	if err = json.Unmarshal(content, loadedState); err != nil {
+		store.logger.Error("Failed to deserialize the state file", zap.String("filePath", store.filePath), zap.Error(err))
+
+		return nil, err
+	}
+
+	store.loaded = true
+	store.state = loadedState
+
+	return store.state, nil
+}
+
+func (store *fileStore) save(newState *state.State) error {
+	content, err := json.Marshal(newState)
+	if err != nil {
+		store.logger.Error("Failed to serialize the state", zap.Error(err))
+
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(store.filePath), 0755); err != nil {
+		store.logger.Error("Failed to create the state directory", zap.String("filePath", store.filePath), zap.Error(err))
+
+		return err
+	}
+
+	// Writing to a temporary file and renaming it over the state file means a crash never leaves a partial file
+	tempFile, err := os.CreateTemp(filepath.Dir(store.filePath), filepath.Base(store.filePath)+".*.tmp")
+	if err != nil {
+		store.logger.Error("Failed to create a temporary state file", zap.String("filePath", store.filePath), zap.Error(err))
+
+		return err
+	}
+
+	defer os.Remove(tempFile.Name())
+
+	if _, err = tempFile.Write(content); err != nil {
+		tempFile.Close()
+		store.logger.Error("Failed to write the temporary state file", zap.String("filePath", tempFile.Name()), zap.Error(err))
+
+		return err
+	}
+
+	if err = tempFile.Close(); err != nil {
+		store.logger.Error("Failed to close the temporary state file", zap.String("filePath", tempFile.Name()), zap.Error(err))
+
+		return err
+	}
+
+	if err = os.Rename(tempFile.Name(), store.filePath); err != nil {
+		store.logger.Error("Failed to replace the state file", zap.String("filePath", store.filePath), zap.Error(err))
+
+		return err
+	}
+
+	store.loaded = true
+	store.state = newState
+
+	return nil
+}
+
+// isCorrupt determines whether the given error means the state file was read but its content can not be parsed
+func isCorrupt(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/state/contract.go
+
+// Package mock_state is a generated GoMock package.
+package mock_state
+
+import (
+	reflect "reflect"
+
+	state "github.com/decentralized-cloud/edge-core/services/state"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStateStoreContract is a mock of StateStoreContract interface.
+type MockStateStoreContract struct {
+	ctrl     *gomock.Controller
+	recorder *MockStateStoreContractMockRecorder
+}
+
+// MockStateStoreContractMockRecorder is the mock recorder for MockStateStoreContract.
+type MockStateStoreContractMockRecorder struct {
+	mock *MockStateStoreContract
+}
+
+// NewMockStateStoreContract creates a new mock instance.
+func NewMockStateStoreContract(ctrl *gomock.Controller) *MockStateStoreContract {
+	mock := &MockStateStoreContract{ctrl: ctrl}
+	mock.recorder = &MockStateStoreContractMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStateStoreContract) EXPECT() *MockStateStoreContractMockRecorder {
+	return m.recorder
+}
+
+// Load mocks base method.
+func (m *MockStateStoreContract) Load() (*state.State, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load")
+	ret0, _ := ret[0].(*state.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockStateStoreContractMockRecorder) Load() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockStateStoreContract)(nil).Load))
+}
+
+// Save mocks base method.
+func (m *MockStateStoreContract) Save(state *state.State) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockStateStoreContractMockRecorder) Save(state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockStateStoreContract)(nil).Save), state)
+}
+
+// Update mocks base method.
+func (m *MockStateStoreContract) Update(update func(*state.State)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", update)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockStateStoreContractMockRecorder) Update(update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStateStoreContract)(nil).Update), update)
+}
@@ -2,11 +2,15 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/decentralized-cloud/edge-core/services/configuration"
 	"github.com/decentralized-cloud/edge-core/services/cron/ipgeolocation"
+	"github.com/decentralized-cloud/edge-core/services/geolocation/chain"
+	"github.com/decentralized-cloud/edge-core/services/state"
 	"github.com/decentralized-cloud/edge-core/services/transport"
 	commonErrors "github.com/micro-business/go-core/system/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -17,15 +21,24 @@ import (
 type transportService struct {
 	logger               *zap.Logger
 	configurationService configuration.ConfigurationContract
+	stateStore           state.StateStoreContract
+	geolocationUpdater   ipgeolocation.GeolocationUpdaterContract
+	geolocationProvider  chain.ChainProviderContract
 }
 
 // NewTransportService creates new instance of the transportService, setting up all dependencies and returns the instance
 // logger: Mandatory. Reference to the logger service
 // configurationService: Mandatory. Reference to the service that provides required configurations
+// stateStore: Mandatory. Reference to the store that keeps the last known geolocation details
+// geolocationUpdater: Mandatory. Reference to the service that updates the node geolocation details on demand
+// geolocationProvider: Mandatory. Reference to the geolocation provider whose circuit breakers determine readiness
 // Returns the new service or error if something goes wrong
 func NewTransportService(
 	logger *zap.Logger,
-	configurationService configuration.ConfigurationContract) (transport.TransportContract, error) {
+	configurationService configuration.ConfigurationContract,
+	stateStore state.StateStoreContract,
+	geolocationUpdater ipgeolocation.GeolocationUpdaterContract,
+	geolocationProvider chain.ChainProviderContract) (transport.TransportContract, error) {
 	if logger == nil {
 		return nil, commonErrors.NewArgumentNilError("logger", "logger is required")
 	}
@@ -34,9 +47,24 @@ func NewTransportService(
 		return nil, commonErrors.NewArgumentNilError("configurationService", "configurationService is required")
 	}
 
+	if stateStore == nil {
+		return nil, commonErrors.NewArgumentNilError("stateStore", "stateStore is required")
+	}
+
+	if geolocationUpdater == nil {
+		return nil, commonErrors.NewArgumentNilError("geolocationUpdater", "geolocationUpdater is required")
+	}
+
+	if geolocationProvider == nil {
+		return nil, commonErrors.NewArgumentNilError("geolocationProvider", "geolocationProvider is required")
+	}
+
 	return &transportService{
 		logger:               logger,
 		configurationService: configurationService,
+		stateStore:           stateStore,
+		geolocationUpdater:   geolocationUpdater,
+		geolocationProvider:  geolocationProvider,
 	}, nil
 }
 
@@ -55,6 +83,9 @@ func (service *transportService) Start() error {
 
 	server.Path("GET", "/live", service.livenessCheckHandler)
 	server.Path("GET", "/ready", service.readinessCheckHandler)
+	server.Path("GET", "/state", service.stateHandler)
+	server.Path("GET", "/geolocation", service.geolocationHandler)
+	server.Path("POST", "/geolocation/refresh", service.refreshHandler)
 	server.NetHTTPPath("GET", "/metrics", promhttp.Handler())
 	service.logger.Info("HTTP transport service started", zap.String("address", config.Addr))
 
@@ -78,11 +109,74 @@ func (service *transportService) livenessCheckHandler(ctx *atreugo.RequestCtx) e
 }
 
 func (service *transportService) readinessCheckHandler(ctx *atreugo.RequestCtx) error {
-	if ipgeolocation.Ready {
-		ctx.Response.SetStatusCode(http.StatusOK)
-	} else {
-		ctx.Response.SetStatusCode(http.StatusServiceUnavailable)
+	circuitBreakers := map[string]map[string]string{}
+	allOpen := true
+
+	for name, statesByFamily := range service.geolocationProvider.GetCircuitBreakerStates() {
+		circuitBreakers[name] = map[string]string{}
+
+		for addressFamily, state := range statesByFamily {
+			circuitBreakers[name][addressFamily] = state.String()
+			allOpen = allOpen && state == chain.CircuitBreakerOpen
+		}
 	}
 
-	return nil
+	// The node can not be located while the circuit breakers of all geolocation providers are open for every
+	// address family, an address family the node has no public address of alone does not make it unready
+	statusCode := http.StatusOK
+	if !ipgeolocation.Ready || (len(circuitBreakers) > 0 && allOpen) {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	return ctx.JSONResponse(map[string]interface{}{"circuitBreakers": circuitBreakers}, statusCode)
+}
+
+func (service *transportService) stateHandler(ctx *atreugo.RequestCtx) error {
+	savedState, err := service.stateStore.Load()
+	if err != nil {
+		return ctx.ErrorResponse(err, http.StatusInternalServerError)
+	}
+
+	if savedState == nil {
+		ctx.Response.SetStatusCode(http.StatusNotFound)
+
+		return nil
+	}
+
+	return ctx.JSONResponse(savedState, http.StatusOK)
+}
+
+func (service *transportService) geolocationHandler(ctx *atreugo.RequestCtx) error {
+	return ctx.JSONResponse(service.geolocationUpdater.GetStatus(), http.StatusOK)
+}
+
+func (service *transportService) refreshHandler(ctx *atreugo.RequestCtx) error {
+	if !ipgeolocation.Live {
+		return ctx.ErrorResponse(errors.New("geolocation updates are disabled"), http.StatusServiceUnavailable)
+	}
+
+	timeout, err := service.configurationService.GetGeolocationRefreshTimeout()
+	if err != nil {
+		return ctx.ErrorResponse(err, http.StatusInternalServerError)
+	}
+
+	refreshCtx, cancelFunc := context.WithTimeout(context.Background(), timeout)
+	defer cancelFunc()
+
+	result, err := service.geolocationUpdater.Refresh(refreshCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ctx.ErrorResponse(err, http.StatusGatewayTimeout)
+	}
+
+	if err != nil {
+		return ctx.ErrorResponse(err, http.StatusServiceUnavailable)
+	}
+
+	// A skipped run, e.g. because the providers are throttled, is not an error of the request
+	statusCode := http.StatusOK
+	if result.Outcome == ipgeolocation.RunFailed {
+		statusCode = http.StatusBadGateway
+	}
+
+	return ctx.JSONResponse(result, statusCode)
 }